@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// config is read from the file given with --config, e.g.
+//
+//	{
+//		"inputs": ["schema/*.ginco"],
+//		"out": "generated",
+//		"emitters": ["go", "go-enum"],
+//		"options": {
+//			"go": { "module": "example.com/models", "package.roleplaying": "rp" },
+//			"go-enum": { "base": "string", "package.roleplaying": "rp" }
+//		}
+//	}
+//
+// Paths, including the dir option of the template emitter, are relative
+// to the directory of the config file.
+// Command line arguments take precedence over the config.
+type config struct {
+	Inputs   []string                     `json:"inputs"`
+	Out      string                       `json:"out"`
+	Emitters []string                     `json:"emitters"`
+	Options  map[string]map[string]string `json:"options"`
+}
+
+func readConfig(path string) (config, error) {
+	cfg := config{}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+
+	if err := json.Unmarshal(content, &cfg); err != nil {
+		return cfg, err
+	}
+
+	dir := filepath.Dir(path)
+	for i, input := range cfg.Inputs {
+		cfg.Inputs[i] = relativeTo(dir, input)
+	}
+
+	if cfg.Out != "" {
+		cfg.Out = relativeTo(dir, cfg.Out)
+	}
+
+	if templates := cfg.Options["template"]["dir"]; templates != "" {
+		cfg.Options["template"]["dir"] = relativeTo(dir, templates)
+	}
+
+	return cfg, nil
+}
+
+func relativeTo(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(dir, path)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/trudso/ginco/stages"
+	"github.com/trudso/ginco/types"
+)
+
+// emitterFactory creates an emitter, either a stages.ModelEmitter or a
+// stages.MetaFileEmitter, from the options given in the config and the
+// directory the output is written to
+type emitterFactory func(options map[string]string, outDir string) (any, error)
+
+// emitterFactories holds the emitters selectable with --emitter
+var emitterFactories = map[string]emitterFactory{
+	"go": func(options map[string]string, outDir string) (any, error) {
+		return stages.GoStructEmitter{
+			Module:   options["module"],
+			Packages: prefixedOptions(options, "package."),
+		}, nil
+	},
+	"go-enum": func(options map[string]string, outDir string) (any, error) {
+		base := options["base"]
+		if base != "" && base != "int" && base != "string" {
+			return nil, fmt.Errorf("unknown base %q, expected int or string", base)
+		}
+
+		return stages.GoEnumEmitter{
+			StringBased: base == "string",
+			Packages:    prefixedOptions(options, "package."),
+		}, nil
+	},
+	"jsonschema": func(options map[string]string, outDir string) (any, error) {
+		return stages.JSONSchemaEmitter{BaseURI: options["base-uri"]}, nil
+	},
+	"sql-postgres": func(options map[string]string, outDir string) (any, error) {
+		return stages.SQLEmitter{Dialect: types.TargetPostgres}, nil
+	},
+	"sql-sqlite": func(options map[string]string, outDir string) (any, error) {
+		return stages.SQLEmitter{Dialect: types.TargetSQLite}, nil
+	},
+	"typescript": func(options map[string]string, outDir string) (any, error) {
+		enums := options["enums"]
+		if enums != "" && enums != "union" && enums != "const" {
+			return nil, fmt.Errorf("unknown enums %q, expected union or const", enums)
+		}
+
+		return stages.TypeScriptEmitter{ConstEnums: enums == "const"}, nil
+	},
+	"protobuf": func(options map[string]string, outDir string) (any, error) {
+		lock, err := stages.ReadProtobufLock(filepath.Join(outDir, stages.PROTOBUF_LOCK_FILE))
+		if err != nil {
+			return nil, fmt.Errorf("reading lock: %w", err)
+		}
+
+		return stages.ProtobufEmitter{Lock: lock, GoPackage: options["go-package"]}, nil
+	},
+	"graphql": func(options map[string]string, outDir string) (any, error) {
+		return stages.GraphQLEmitter{}, nil
+	},
+	"openapi": func(options map[string]string, outDir string) (any, error) {
+		format := options["format"]
+		if format != "" && format != "yaml" && format != "json" {
+			return nil, fmt.Errorf("unknown format %q, expected yaml or json", format)
+		}
+
+		return stages.OpenAPIEmitter{Title: options["title"], Version: options["version"], JSON: format == "json"}, nil
+	},
+	"template": func(options map[string]string, outDir string) (any, error) {
+		if options["dir"] == "" {
+			return nil, fmt.Errorf("the dir option with the templates is required")
+		}
+
+		return stages.NewTemplateEmitter(options["dir"])
+	},
+}
+
+func emitterNames() string {
+	names := []string{}
+	for name := range emitterFactories {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return strings.Join(names, ", ")
+}
+
+// prefixedOptions returns the options starting with prefix, without the
+// prefix, e.g. "package.roleplaying" -> "roleplaying"
+func prefixedOptions(options map[string]string, prefix string) map[string]string {
+	prefixed := map[string]string{}
+	for key, value := range options {
+		if name, found := strings.CutPrefix(key, prefix); found {
+			prefixed[name] = value
+		}
+	}
+
+	return prefixed
+}
+
+func addEmitter(pipeline *stages.Pipeline, name string, options map[string]string) error {
+	factory, found := emitterFactories[name]
+	if !found {
+		return fmt.Errorf("unknown emitter %q, expected one of %s", name, emitterNames())
+	}
+
+	emitter, err := factory(options, pipeline.OutDir)
+	if err != nil {
+		return fmt.Errorf("emitter %s: %w", name, err)
+	}
+
+	switch emitter := emitter.(type) {
+	case stages.MetaFileEmitter:
+		pipeline.FileEmitters = append(pipeline.FileEmitters, emitter)
+	case stages.ModelEmitter:
+		pipeline.Emitters = append(pipeline.Emitters, emitter)
+	default:
+		return fmt.Errorf("emitter %s is neither a model nor a file emitter", name)
+	}
+
+	return nil
+}
@@ -1,34 +1,217 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/trudso/ginco/stages"
+	"github.com/trudso/ginco/types"
 )
 
+// exit codes
+const (
+	EXIT_OK     = 0
+	EXIT_ERRORS = 1
+	EXIT_USAGE  = 2
+)
+
+// stringList is a repeatable string flag
+type stringList []string
+
+func (self *stringList) String() string {
+	return strings.Join(*self, ",")
+}
+
+func (self *stringList) Set(value string) error {
+	*self = append(*self, value)
+	return nil
+}
+
 func main() {
-	reader := strings.NewReader(`
-	package roleplaying {
-		@changeset
-		model Character {
-			fields {
-				@noChangeset
-				1= id uuid
-				?= name string
-				?= age number
-				1- type CharacterType
-				*= skills Skill
-	    	}
-		}
-	}`)
-
-	parser := stages.GincoMetaFileParser{}
-	file, err := parser.Parse(reader)
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: generate [flags] <file or glob>...\n\nFlags:\n")
+		flags.PrintDefaults()
+		fmt.Fprintf(stderr, "\nEmitters: %s\n", emitterNames())
+	}
+
+	out := flags.String("out", "", "directory to write the generated files to (default \".\")")
+	configPath := flags.String("config", "", "JSON config file with inputs, out, emitters and emitter options")
+	dryRun := flags.Bool("dry-run", false, "print the files that would be generated without writing them")
+	verbose := flags.Bool("verbose", false, "print progress")
+	var emitters stringList
+	flags.Var(&emitters, "emitter", "emitter to run, can be repeated")
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return EXIT_OK
+		}
+		return EXIT_USAGE
+	}
+
+	cfg := config{}
+	if *configPath != "" {
+		var err error
+		cfg, err = readConfig(*configPath)
+		if err != nil {
+			fmt.Fprintf(stderr, "error: reading config: %v\n", err)
+			return EXIT_USAGE
+		}
+	}
+
+	patterns := flags.Args()
+	if len(patterns) == 0 {
+		patterns = cfg.Inputs
+	}
+	if len(emitters) == 0 {
+		emitters = cfg.Emitters
+	}
+	if *out == "" {
+		*out = cfg.Out
+	}
+	if *out == "" {
+		*out = "."
+	}
+
+	inputs, err := expandInputs(patterns)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return EXIT_USAGE
+	}
+
+	if len(inputs) == 0 {
+		flags.Usage()
+		return EXIT_USAGE
+	}
+
+	pipeline := stages.Pipeline{
+		Inputs:           inputs,
+		FileTransformers: []stages.MetaFileTransformer{stages.InheritanceTransformer{}},
+		OutDir:           *out,
+	}
+
+	for _, name := range emitters {
+		if err := addEmitter(&pipeline, name, cfg.Options[name]); err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return EXIT_USAGE
+		}
+	}
+
+	logf := func(format string, args ...any) {
+		if *verbose {
+			fmt.Fprintf(stdout, format+"\n", args...)
+		}
+	}
+
+	logf("parsing %s", strings.Join(inputs, ", "))
+	file, err := pipeline.Parse()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Unexpected error: %+v\n", err)
+		printError(stderr, err)
+		return EXIT_ERRORS
 	}
 
-	fmt.Printf("Ginco out: %+v\n", file)
+	for _, pkg := range file.Packages {
+		logf("package %s: %d models, %d enums, %d scalars", pkg.Name, len(pkg.Models), len(pkg.Enums), len(pkg.Scalars))
+	}
+
+	file, err = pipeline.Transform(file)
+	if err != nil {
+		printError(stderr, err)
+		return EXIT_ERRORS
+	}
+
+	if len(emitters) == 0 {
+		logf("no emitters selected, only validated the inputs")
+		return EXIT_OK
+	}
+
+	results, err := pipeline.Emit(file)
+	if err != nil {
+		printError(stderr, err)
+		return EXIT_ERRORS
+	}
+
+	if *dryRun {
+		for _, result := range results {
+			fmt.Fprintln(stdout, filepath.Join(*out, result.Path))
+		}
+		return EXIT_OK
+	}
+
+	written, err := pipeline.Write(results)
+	for _, path := range written {
+		logf("wrote %s", path)
+	}
+
+	if err != nil {
+		printError(stderr, err)
+		return EXIT_ERRORS
+	}
+
+	return EXIT_OK
+}
+
+// expandInputs expands the glob patterns into the files they match
+func expandInputs(patterns []string) ([]string, error) {
+	inputs := []string{}
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return inputs, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+
+		if len(matches) == 0 && strings.ContainsAny(pattern, "*?[") {
+			return inputs, fmt.Errorf("no files match %q", pattern)
+		}
+
+		if len(matches) == 0 {
+			// reported as a missing file by the parser
+			matches = []string{pattern}
+		}
+
+		inputs = append(inputs, matches...)
+	}
+
+	return inputs, nil
+}
+
+// printError prints every diagnostic err consists of, including
+// diagnostics wrapped by other errors
+func printError(w io.Writer, err error) {
+	switch e := err.(type) {
+	case types.Diagnostic:
+		fmt.Fprintf(w, "%s: %s[%s]: %s\n", e.Position, e.Severity, e.Code, e.Message)
+		if e.Hint != "" {
+			fmt.Fprintf(w, "\thint: %s\n", e.Hint)
+		}
+	case types.Diagnostics:
+		for _, diagnostic := range e {
+			printError(w, diagnostic)
+		}
+	case interface{ Unwrap() []error }:
+		for _, wrapped := range e.Unwrap() {
+			printError(w, wrapped)
+		}
+	default:
+		var diagnostics types.Diagnostics
+		var diagnostic types.Diagnostic
+		switch {
+		case errors.As(err, &diagnostics):
+			printError(w, diagnostics)
+		case errors.As(err, &diagnostic):
+			printError(w, diagnostic)
+		default:
+			fmt.Fprintf(w, "error: %v\n", err)
+		}
+	}
 }
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trudso/ginco/types"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	return dir
+}
+
+func TestRun(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"valid.ginco": `package poc {
+	model Poc {
+		fields {
+			=1 id uuid
+		}
+	}
+}`,
+		"invalid.ginco": `package poc {
+	model Poc {
+		fields {
+			=1 id Missing
+		}
+	}
+}`,
+		"config.json":         `{ "inputs": ["valid.ginco"], "out": "generated", "emitters": ["go"] }`,
+		"templates/name.tmpl": `{{output (printf "%s.txt" .Model.Name)}}{{.Model.Name}}`,
+		"template.json":       `{ "inputs": ["valid.ginco"], "out": "generated", "emitters": ["template"], "options": { "template": { "dir": "templates" } } }`,
+	})
+
+	tests := []struct {
+		name     string
+		args     []string
+		code     int
+		stdout   string
+		stderr   string
+		expected []string
+	}{
+		{"validate only", []string{filepath.Join(dir, "valid.ginco")}, EXIT_OK, "", "", nil},
+		{"invalid", []string{"--dry-run", "--emitter", "go", "--out", "out", filepath.Join(dir, "*.ginco")}, EXIT_ERRORS, "", "error[unresolved-type]", nil},
+		{"dry run valid", []string{"--dry-run", "--emitter", "go", "--out", "out", filepath.Join(dir, "valid.ginco")}, EXIT_OK, filepath.Join("out", "poc", "poc.go"), "", nil},
+		{"config", []string{"--config", filepath.Join(dir, "config.json")}, EXIT_OK, "", "", []string{filepath.Join(dir, "generated", "poc", "poc.go")}},
+		{"template config", []string{"--config", filepath.Join(dir, "template.json")}, EXIT_OK, "", "", []string{filepath.Join(dir, "generated", "Poc.txt")}},
+		{"unknown emitter", []string{"--emitter", "nope", filepath.Join(dir, "valid.ginco")}, EXIT_USAGE, "", "unknown emitter \"nope\"", nil},
+		{"no match", []string{filepath.Join(dir, "*.missing")}, EXIT_USAGE, "", "no files match", nil},
+		{"no inputs", []string{}, EXIT_USAGE, "", "Usage", nil},
+		{"unknown flag", []string{"--nope"}, EXIT_USAGE, "", "flag provided but not defined", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+			code := run(test.args, &stdout, &stderr)
+			assert.Equal(t, test.code, code, stderr.String())
+			assert.Contains(t, stdout.String(), test.stdout)
+			assert.Contains(t, stderr.String(), test.stderr)
+			for _, path := range test.expected {
+				assert.FileExists(t, path)
+			}
+		})
+	}
+}
+
+func TestPrintError(t *testing.T) {
+	diagnostic := types.Diagnostic{
+		Severity: types.SeverityError,
+		Code:     types.CodeUnresolvedType,
+		Message:  "Unresolved type poc.Missing",
+		Position: types.Position{File: "poc.ginco", Line: 4, Column: 10},
+		Hint:     "Declare Missing in package poc",
+	}
+	printed := "poc.ginco:4:10: error[unresolved-type]: Unresolved type poc.Missing\n\thint: Declare Missing in package poc\n"
+
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"diagnostic", diagnostic, printed},
+		{"diagnostics", types.Diagnostics{diagnostic, diagnostic}, printed + printed},
+		{"wrapped diagnostic", fmt.Errorf("emitter go: %w", diagnostic), printed},
+		{"wrapped diagnostics", fmt.Errorf("emitter go: %w", types.Diagnostics{diagnostic}), printed},
+		{"joined", errors.Join(errors.New("failed"), fmt.Errorf("model poc.Poc: %w", diagnostic)), "error: failed\n" + printed},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := bytes.Buffer{}
+			printError(&output, test.err)
+			assert.Equal(t, test.expected, output.String())
+		})
+	}
+}
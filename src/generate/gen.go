@@ -1,4 +1,3 @@
 package main
 
 //go:generate go run gen_poc.go
-//go:generate go fmt PocTest.go
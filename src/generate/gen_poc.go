@@ -3,24 +3,23 @@ package main
 import (
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/trudso/ginco/stages"
-	"github.com/trudso/ginco/types"
 )
 
 func main() {
-	generator := stages.PocModelCodeGenerator{}
-	results, _ := generator.Generate(types.MetaModel{})
-	for _, result := range results {
-		file, err := os.Create(result.Path)
-		if err != nil {
-			log.Fatal(err)
-		}
-		defer file.Close()
+	pipeline := stages.Pipeline{
+		Inputs:       []string{"poc.ginco"},
+		FileEmitters: []stages.MetaFileEmitter{stages.GoStructEmitter{}},
+		OutDir:       ".",
+	}
 
-		fmt.Fprintf(file, result.Content)
+	written, err := pipeline.Run()
+	if err != nil {
+		log.Fatal(err)
+	}
 
-		fmt.Println("Done")
+	for _, path := range written {
+		fmt.Println("Done", path)
 	}
 }
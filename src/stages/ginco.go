@@ -1,13 +1,124 @@
 package stages
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/trudso/ginco/types"
 )
 
-type GincoMetaFileParser struct{}
+type GincoMetaFileParser struct {
+	// Dir is the directory imports are resolved against when parsing
+	// from a reader. Defaults to the working directory.
+	Dir string
+}
 
 func (self GincoMetaFileParser) Parse(reader io.Reader) (types.MetaFile, error) {
-	return types.MetaFile{}, nil
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return types.MetaFile{}, err
+	}
+
+	file, _, err := parseMetaFile(string(content), 0)
+	if err != nil && !isRecovered(err) {
+		return file, err
+	}
+	diagnostics := collectError(types.Diagnostics{}, err)
+
+	resolver := newImportResolver()
+	merged, err := resolver.resolve(file, self.Dir)
+	diagnostics = collectError(diagnostics, err)
+	return merged, diagnostics.Err()
+}
+
+// ParseFile parses the file at path along with everything it imports
+func (self GincoMetaFileParser) ParseFile(path string) (types.MetaFile, error) {
+	resolver := newImportResolver()
+	return resolver.load(path, types.Position{})
+}
+
+// ParseFiles parses every file at paths along with everything they
+// import into a single MetaFile. Files are only merged once, even when
+// imported by several of them.
+func (self GincoMetaFileParser) ParseFiles(paths ...string) (types.MetaFile, error) {
+	resolver := newImportResolver()
+	merged := types.MetaFile{}
+	diagnostics := types.Diagnostics{}
+	for _, path := range paths {
+		file, err := resolver.load(path, types.Position{})
+		diagnostics = collectError(diagnostics, err)
+		merged.Imports = append(merged.Imports, file.Imports...)
+		merged.Packages = append(merged.Packages, file.Packages...)
+	}
+
+	return merged, diagnostics.Err()
+}
+
+/*
+	# comment
+	import ./roleplaying.ginco
+
+	package roleplaying {
+		...
+	}
+
+	package horror {
+		...
+	}
+*/
+func parseMetaFile(content string, idx int) (types.MetaFile, int, error) {
+	file := types.MetaFile{}
+	diagnostics := types.Diagnostics{}
+	curIdx := idx
+
+	// resync continues at the next top level declaration after a failed one
+	resync := func(err error) {
+		diagnostics = collectError(diagnostics, err)
+		curIdx = nextLineStartingWith(content, max(curIdx, errorOffset(err, curIdx)), IMPORT, PACKAGE)
+	}
+
+	for {
+		token, nextIdx, err := popToken(content, curIdx)
+		if err != nil {
+			resync(err)
+			continue
+		}
+
+		switch {
+		case token.Type == TT_EOF:
+			if len(diagnostics) > 0 {
+				return file, len(content), diagnostics
+			}
+			return file, len(content), nil
+		case token.Type == TT_COMMENT:
+			curIdx = nextIdx
+		case token.Type == TT_IDENTIFIER && token.Value == IMPORT:
+			metaImport, nextIdx, err := parseImport(content, curIdx)
+			if err != nil {
+				resync(err)
+				continue
+			}
+
+			locate(&metaImport.Position, content)
+			file.Imports = append(file.Imports, metaImport)
+			curIdx = nextIdx
+		case token.Type == TT_IDENTIFIER && token.Value == PACKAGE:
+			pkg, nextIdx, err := parsePackage(content, curIdx)
+			if err != nil && !isRecovered(err) {
+				resync(err)
+				continue
+			}
+
+			diagnostics = collectError(diagnostics, err)
+			locatePackage(&pkg, content)
+			file.Packages = append(file.Packages, pkg)
+			curIdx = nextIdx
+		case token.Type == TT_IDENTIFIER:
+			err := formatParsingError(fmt.Sprintf("Unknown top level keyword %q", token.Value), content, token.Position)
+			err.Hint = fmt.Sprintf("Only %q and %q are allowed at the top level", IMPORT, PACKAGE)
+			resync(err)
+		default:
+			resync(formatParsingError("Unexpected content at top level", content, firstValidTokenIndex(content, curIdx)))
+		}
+	}
 }
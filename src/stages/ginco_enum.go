@@ -1,14 +1,16 @@
 package stages
 
 import (
+	"fmt"
 	"slices"
 
 	"github.com/trudso/ginco/types"
 )
 
 const (
-	ENUM = "enum"
-	LITERALS = "literals"
+	ENUM        = "enum"
+	ENUMERATION = "enumeration"
+	LITERALS    = "literals"
 )
 
 /*
@@ -22,32 +24,42 @@ const (
 */
 func parseEnum(content string, idx int) (types.MetaEnum, int, error) {
 	enum := types.MetaEnum{}
-	token, nextIdx, err := popExpectedToken( content, idx, TT_IDENTIFIER, ENUM )
+	token, nextIdx, err := popToken(content, idx)
 	if err != nil {
 		return enum, idx, err
 	}
 
+	if !isEnumKeyword(token) {
+		return enum, idx, formatParsingError(fmt.Sprintf("Expected %q or %q, but found %q", ENUM, ENUMERATION, token.Value), content, idx)
+	}
+
 	token, nextIdx, err = popIdentifier(content, nextIdx)
 	if err != nil {
 		return enum, nextIdx, err
 	}
 
 	enum.Name = token.Value
-	token, nextIdx, err = popToken(content, nextIdx)
-	if token.Type == TT_SCOPE {
-		scopeContent := token.Value
-		token, _, err := popToken(scopeContent, 0)
-		if err != nil {
-			return enum, nextIdx, err
-		}
+	enum.Position.Offset = firstValidTokenIndex(content, idx)
+	scope, nextIdx, err := popBody(content, nextIdx, fmt.Sprintf("%s %s", ENUM, enum.Name))
+	if err != nil {
+		return enum, nextIdx, err
+	}
 
-		if token.Type == TT_IDENTIFIER && token.Value == LITERALS {
-			literals, _, err := parseEnumLiterals(scopeContent, 0)
-			if err != nil {
-				return enum, nextIdx, err
-			}
+	scopeStart := scopeContentStart(scope, nextIdx)
+	token, _, err = popToken(scope.Value, 0)
+	if err != nil {
+		return enum, nextIdx, relocateError(err, scopeStart, content)
+	}
+
+	if token.Type == TT_IDENTIFIER && token.Value == LITERALS {
+		literals, _, err := parseEnumLiterals(scope.Value, 0)
+		if err != nil && !isRecovered(err) {
+			return enum, nextIdx, relocateError(err, scopeStart, content)
+		}
 
-			enum.Literals = literals
+		enum.Literals = literals
+		if err != nil {
+			return enum, nextIdx, relocateError(err, scopeStart, content)
 		}
 	}
 
@@ -67,20 +79,33 @@ func parseEnumLiterals(content string, idx int) ([]string, int, error) {
 		return nil, nextIdx, err
 	}
 
+	diagnostics := types.Diagnostics{}
+	scopeStart := scopeContentStart(scope, nextIdx)
 	scopeIdx := 0
 	for !isEOF(scope.Value, scopeIdx) {
-		var literalToken Token
-		literalToken, scopeIdx, err = popIdentifier(scope.Value, scopeIdx)
+		literalToken, literalNextIdx, err := popIdentifier(scope.Value, scopeIdx)
 		if err != nil {
-			return literals, scopeIdx, err
+			// resynchronize at the next literal line
+			diagnostics = collectError(diagnostics, err)
+			scopeIdx = nextLineIndex(scope.Value, max(scopeIdx, errorOffset(err, scopeIdx)))
+			continue
 		}
 
+		scopeIdx = literalNextIdx
 		if slices.Contains( literals, literalToken.Value ) {
-			return literals, nextIdx, formatParsingError("duplicate literal found", content, literalToken.Position)
+			diagnostics = collectError(diagnostics, formatParsingError("duplicate literal found", scope.Value, literalToken.Position))
+			continue
 		}
 
 		literals = append(literals, literalToken.Value)
 	}
 
+	if len(diagnostics) > 0 {
+		return literals, nextIdx, relocateError(diagnostics, scopeStart, content)
+	}
 	return literals, nextIdx, nil
 }
+
+func isEnumKeyword(token Token) bool {
+	return token.Type == TT_IDENTIFIER && (token.Value == ENUM || token.Value == ENUMERATION)
+}
@@ -14,7 +14,9 @@ func TestParseEnum(t *testing.T) {
 		expectedNextIds     int
 		expectedErrorValues []string
 	}{
-		{`enum {}`, "", nil, 5, []string{"[1:6]", "No identifier found"}},
+		{`enum {}`, "", nil, 5, []string{"1:6", "No identifier found"}},
+		{`enum CharacterType`, "CharacterType", nil, 18, []string{"Expected { after enum CharacterType"}},
+		{`enum CharacterType 5`, "CharacterType", nil, 18, []string{"1:20", "Expected { after enum CharacterType"}},
 		{`enum SomeEnum {
         literals {}
     }`, "SomeEnum", []string{}, 41, []string{}},
@@ -31,7 +33,14 @@ func TestParseEnum(t *testing.T) {
             b
             a
         }
-    }`, "SomeEnum", []string{}, 92, []string{"duplicate literal found"}},
+    }`, "SomeEnum", []string{"a", "b"}, 92, []string{"duplicate literal found"}},
+		{`enumeration CharacterType {
+        literals {
+            player
+            boss
+        }
+    }`, "CharacterType", []string{"player", "boss"}, 98, []string{}},
+		{`model CharacterType {}`, "", nil, 0, []string{`Expected "enum" or "enumeration", but found "model"`}},
 	}
 
 	for _, tc := range testCases {
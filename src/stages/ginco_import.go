@@ -0,0 +1,141 @@
+package stages
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/trudso/ginco/types"
+)
+
+/*
+	import ./roleplaying.ginco
+*/
+func parseImport(content string, idx int) (types.MetaImport, int, error) {
+	metaImport := types.MetaImport{}
+	_, nextIdx, err := popExpectedToken(content, idx, TT_IDENTIFIER, IMPORT)
+	if err != nil {
+		return metaImport, idx, err
+	}
+
+	metaImport.Position.Offset = firstValidTokenIndex(content, idx)
+	path, nextIdx, err := popPath(content, nextIdx)
+	if err != nil {
+		return metaImport, nextIdx, err
+	}
+
+	metaImport.Path = path.Value
+	return metaImport, nextIdx, nil
+}
+
+// importResolver loads imported files, relative to the importing file,
+// and merges their packages into a single MetaFile.
+type importResolver struct {
+	// visited holds every file loaded so far, so repeated imports are only merged once
+	visited map[string]bool
+	// stack holds the chain of files currently being loaded, used to detect cycles
+	stack []string
+}
+
+func newImportResolver() *importResolver {
+	return &importResolver{
+		visited: map[string]bool{},
+	}
+}
+
+// load parses the file at path and everything it imports. importedAt is
+// the position of the import directive loading it, if any.
+func (self *importResolver) load(path string, importedAt types.Position) (types.MetaFile, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return types.MetaFile{}, err
+	}
+
+	if slices.Contains(self.stack, absPath) {
+		cycle := append(slices.Clone(self.stack), absPath)
+		return types.MetaFile{}, self.importError(types.CodeImportCycle, fmt.Sprintf("Import cycle detected: %s", strings.Join(cycle, " -> ")), path, importedAt)
+	}
+
+	if self.visited[absPath] {
+		return types.MetaFile{}, nil
+	}
+	self.visited[absPath] = true
+
+	content, err := os.ReadFile(absPath)
+	if err != nil {
+		return types.MetaFile{}, self.importError(types.CodeImport, err.Error(), path, importedAt)
+	}
+
+	file, _, err := parseMetaFile(string(content), 0)
+	if err != nil && !isRecovered(err) {
+		return file, withFile(err, path)
+	}
+	setFile(&file, path)
+	diagnostics := collectError(types.Diagnostics{}, withFile(err, path))
+
+	self.stack = append(self.stack, absPath)
+	defer func() { self.stack = self.stack[:len(self.stack)-1] }()
+
+	merged, err := self.resolve(file, filepath.Dir(absPath))
+	diagnostics = collectError(diagnostics, err)
+	return merged, diagnostics.Err()
+}
+
+// resolve returns file with the packages of all its imports merged in,
+// imported packages first. Failing imports are reported, but do not
+// prevent the remaining imports from being merged.
+func (self *importResolver) resolve(file types.MetaFile, dir string) (types.MetaFile, error) {
+	merged := types.MetaFile{Imports: file.Imports}
+	diagnostics := types.Diagnostics{}
+	for _, metaImport := range file.Imports {
+		importPath := metaImport.Path
+		if !filepath.IsAbs(importPath) {
+			importPath = filepath.Join(dir, importPath)
+		}
+
+		imported, err := self.load(importPath, metaImport.Position)
+		diagnostics = collectError(diagnostics, err)
+		merged.Packages = append(merged.Packages, imported.Packages...)
+	}
+
+	merged.Packages = append(merged.Packages, file.Packages...)
+	return merged, diagnostics.Err()
+}
+
+// importError reports a failing import at the import directive loading
+// path, or at path itself when it was not imported
+func (self *importResolver) importError(code, message, path string, importedAt types.Position) types.Diagnostic {
+	position := importedAt
+	if position == (types.Position{}) {
+		position = types.Position{File: path}
+	}
+
+	return types.Diagnostic{
+		Severity: types.SeverityError,
+		Code:     code,
+		Message:  message,
+		Position: position,
+	}
+}
+
+// withFile records the file a parsing error was found in
+func withFile(err error, file string) error {
+	switch diagnostics := err.(type) {
+	case nil:
+		return nil
+	case types.Diagnostic:
+		diagnostics.Position.File = file
+		return diagnostics
+	case types.Diagnostics:
+		located := make(types.Diagnostics, len(diagnostics))
+		for i, diagnostic := range diagnostics {
+			diagnostic.Position.File = file
+			located[i] = diagnostic
+		}
+		return located
+	}
+
+	return fmt.Errorf("%s: %w", file, err)
+}
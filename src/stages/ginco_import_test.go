@@ -0,0 +1,143 @@
+package stages
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trudso/ginco/types"
+)
+
+func TestParseImport(t *testing.T) {
+	testCases := []struct {
+		content             string
+		expectedPath        string
+		expectedNextIdx     int
+		expectedErrorValues []string
+	}{
+		{"import ./roleplaying.ginco", "./roleplaying.ginco", 26, nil},
+		{"import ../shared/base.ginco\npackage a {}", "../shared/base.ginco", 27, nil},
+		{"import", "", 6, []string{"No path found"}},
+		{"package a {}", "", 0, []string{`Expected value "import"`}},
+	}
+
+	for _, tc := range testCases {
+		metaImport, nextIdx, err := parseImport(tc.content, 0)
+		assertErrorContains(t, err, tc.expectedErrorValues)
+		assert.Equal(t, tc.expectedPath, metaImport.Path)
+		assert.Equal(t, tc.expectedNextIdx, nextIdx)
+	}
+}
+
+func writeGincoFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	return dir
+}
+
+func packageNames(t *testing.T, dir, path string) ([]string, error) {
+	file, err := GincoMetaFileParser{}.ParseFile(filepath.Join(dir, path))
+	names := []string{}
+	for _, pkg := range file.Packages {
+		names = append(names, pkg.Name)
+	}
+
+	return names, err
+}
+
+func TestParseFileWithImports(t *testing.T) {
+	dir := writeGincoFiles(t, map[string]string{
+		"roleplaying.ginco": `package roleplaying {
+			model Character {
+				fields {
+					=1 id uuid
+				}
+			}
+		}`,
+		"horror.ginco": `import ./roleplaying.ginco
+
+		package horror {
+			model Vampire {
+				fields {
+					-1 clan Clan
+				}
+			}
+		}`,
+	})
+
+	names, err := packageNames(t, dir, "horror.ginco")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"roleplaying", "horror"}, names)
+}
+
+func TestParseFileDeduplicatesImports(t *testing.T) {
+	dir := writeGincoFiles(t, map[string]string{
+		"base.ginco":        `package base {}`,
+		"left/left.ginco":   "import ../base.ginco\npackage left {}",
+		"right/right.ginco": "import ../base.ginco\nimport ./../base.ginco\npackage right {}",
+		"main.ginco":        "import ./left/left.ginco\nimport ./right/right.ginco\npackage main {}",
+	})
+
+	names, err := packageNames(t, dir, "main.ginco")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"base", "left", "right", "main"}, names)
+}
+
+func TestParseFileDetectsImportCycles(t *testing.T) {
+	dir := writeGincoFiles(t, map[string]string{
+		"a.ginco": "import ./b.ginco\npackage a {}",
+		"b.ginco": "import ./c.ginco\npackage b {}",
+		"c.ginco": "import ./a.ginco\npackage c {}",
+	})
+
+	_, err := packageNames(t, dir, "a.ginco")
+	assertErrorContains(t, err, []string{"Import cycle detected", "a.ginco -> ", "b.ginco -> ", "c.ginco -> "})
+}
+
+func TestParseFileReportsMissingImports(t *testing.T) {
+	dir := writeGincoFiles(t, map[string]string{
+		"a.ginco": "import ./missing.ginco\npackage a {}",
+	})
+
+	_, err := packageNames(t, dir, "a.ginco")
+	assertErrorContains(t, err, []string{"missing.ginco"})
+}
+
+func TestParseResolvesImportsAgainstDir(t *testing.T) {
+	dir := writeGincoFiles(t, map[string]string{
+		"roleplaying.ginco": `package roleplaying {}`,
+	})
+
+	parser := GincoMetaFileParser{Dir: dir}
+	file, err := parser.Parse(strings.NewReader("import ./roleplaying.ginco\npackage horror {}"))
+	assert.NoError(t, err)
+	assert.Equal(t, []types.MetaImport{{Position: types.Position{Line: 1, Column: 1}, Path: "./roleplaying.ginco"}}, file.Imports)
+	assert.Equal(t, 2, len(file.Packages))
+	assert.Equal(t, "roleplaying", file.Packages[0].Name)
+}
+
+func TestParseFileReportsErrorsOfAllFiles(t *testing.T) {
+	dir := writeGincoFiles(t, map[string]string{
+		"roleplaying.ginco": "package roleplaying {\n\tmodel Character {\n\t\tfields {\n\t\t\t=1 $id uuid\n\t\t}\n\t}\n}",
+		"horror.ginco":      "import ./roleplaying.ginco\nimport ./missing.ginco\n\npackage horror {\n\tsomething\n}",
+	})
+
+	names, err := packageNames(t, dir, "horror.ginco")
+	assert.Equal(t, []string{"roleplaying", "horror"}, names)
+
+	var diagnostics types.Diagnostics
+	assert.True(t, errors.As(err, &diagnostics))
+	assert.Equal(t, 3, len(diagnostics))
+	assert.Equal(t, filepath.Join(dir, "horror.ginco")+":5:2", diagnostics[0].Position.String())
+	assert.Equal(t, filepath.Join(dir, "roleplaying.ginco")+":4:7", diagnostics[1].Position.String())
+	assert.Equal(t, types.CodeImport, diagnostics[2].Code)
+	assert.Equal(t, filepath.Join(dir, "horror.ginco")+":2:1", diagnostics[2].Position.String())
+}
@@ -14,6 +14,8 @@ const (
 	NON_NULL    = "1"
 	NULLABLE    = "?"
 
+	PACKAGE_SEPARATOR = "."
+
 	IMPORT       = "import"
 	PACKAGE      = "package"
 	TRAIT_SYMBOL = "@"
@@ -42,23 +44,34 @@ func parseModel(content string, idx int) (types.MetaModel, int, error) {
 	}
 
 	token, nextIdx, err = popIdentifier(content, nextIdx)
+	if err != nil {
+		return model, nextIdx, err
+	}
+
 	model.Name = token.Value
+	model.Position.Offset = firstValidTokenIndex(content, idx)
 
-	token, nextIdx, err = popToken(content, nextIdx)
-	if token.Type == TT_SCOPE {
-		scopeContent := token.Value
-		token, _, err := popToken(scopeContent, 0)
-		if err != nil {
-			return model, nextIdx, err
-		}
+	scope, nextIdx, err := popBody(content, nextIdx, fmt.Sprintf("%s %s", MODEL, model.Name))
+	if err != nil {
+		return model, nextIdx, err
+	}
 
-		if token.Type == TT_IDENTIFIER && token.Value == MODEL_FIELDS {
-			fields, _, err := parseModelFields(scopeContent, 0)
-			if err != nil {
-				return model, nextIdx, err
-			}
+	scopeStart := scopeContentStart(scope, nextIdx)
+	token, _, err = popToken(scope.Value, 0)
+	if err != nil {
+		return model, nextIdx, relocateError(err, scopeStart, content)
+	}
 
-			model.Fields = fields
+	if token.Type == TT_IDENTIFIER && token.Value == MODEL_FIELDS {
+		fields, _, err := parseModelFields(scope.Value, 0)
+		if err != nil && !isRecovered(err) {
+			return model, nextIdx, relocateError(err, scopeStart, content)
+		}
+
+		model.Fields = fields
+		walkFieldPositions(model.Fields, shiftBy(scopeStart))
+		if err != nil {
+			return model, nextIdx, relocateError(err, scopeStart, content)
 		}
 	}
 
@@ -77,38 +90,27 @@ func parseModelFields(content string, idx int) ([]types.MetaModelField, int, err
 	}
 
 	fields := []types.MetaModelField{}
+	diagnostics := types.Diagnostics{}
+	scopeStart := scopeContentStart(scope, nextIdx)
 	scopeIdx := 0
 	for !isEOF(scope.Value, scopeIdx) {
-		var field types.MetaModelField
-		field, scopeIdx, err = parseModelField(scope.Value, scopeIdx)
+		field, fieldNextIdx, err := parseModelField(scope.Value, scopeIdx)
 		if err != nil {
-			return fields, scopeIdx, err
+			// resynchronize at the next field line
+			diagnostics = collectError(diagnostics, err)
+			scopeIdx = nextLineIndex(scope.Value, max(scopeIdx, errorOffset(err, scopeIdx)))
+			continue
 		}
 
 		fields = append(fields, field)
+		scopeIdx = fieldNextIdx
 	}
 
-	return fields, scopeIdx, nil
-}
-
-func parseTrait(content string, idx int) (types.MetaTrait, int, error) {
-	trait := types.MetaTrait{}
-	symbolToken, nextIdx, err := popSymbol(content, idx)
-	if err != nil {
-		return trait, nextIdx, err
+	walkFieldPositions(fields, shiftBy(scopeStart))
+	if len(diagnostics) > 0 {
+		return fields, scopeIdx, relocateError(diagnostics, scopeStart, content)
 	}
-
-	if symbolToken.Value != TRAIT_SYMBOL {
-		return trait, idx, formatParsingError("No trait found", content, symbolToken.Position)
-	}
-
-	identifier, nextIdx, err := popIdentifier(content, nextIdx)
-	if err != nil {
-		return trait, nextIdx, err
-	}
-
-	trait.Name = identifier.Value
-	return trait, nextIdx, nil
+	return fields, scopeIdx, nil
 }
 
 func parseModelField(content string, idx int) (types.MetaModelField, int, error) {
@@ -136,8 +138,12 @@ func parseModelField(content string, idx int) (types.MetaModelField, int, error)
 			case AGGREGATION:
 				field.Ownership = types.Aggregation
 			}
+			field.Position.Offset = symbolToken.Position
 
-			multiplicy, nextIdx, err := popSingleRune(content, nextIdx)
+			// a field is declared on a single line, so a broken field
+			// never swallows the field on the line following it
+			line := content[:nextLineIndex(content, symbolToken.Position)]
+			multiplicy, nextIdx, err := popSingleRune(line, nextIdx)
 			if err != nil {
 				return field, nextIdx, err
 			}
@@ -148,16 +154,18 @@ func parseModelField(content string, idx int) (types.MetaModelField, int, error)
 				field.Cardinality = types.One
 			case COLLECTION:
 				field.Cardinality = types.Collection
+			default:
+				return field, nextIdx, formatParsingError(fmt.Sprintf("Unexpected multiplicity %s, expected one of %s%s%s", multiplicy.Value, NULLABLE, NON_NULL, COLLECTION), content, multiplicy.Position)
 			}
 
-			name, nextIdx, err := popIdentifier(content, nextIdx)
+			name, nextIdx, err := popIdentifier(line, nextIdx)
 			if err != nil {
 				return field, nextIdx, err
 			}
 
 			field.Name = name.Value
 
-			metaType, nextIdx, err := parseMetaType(content, nextIdx)
+			metaType, nextIdx, err := parseMetaType(line, nextIdx)
 			if err != nil {
 				return field, nextIdx, err
 			}
@@ -170,6 +178,8 @@ func parseModelField(content string, idx int) (types.MetaModelField, int, error)
 	}
 }
 
+// parseMetaType parses a type reference which is either
+// unqualified (Character) or package qualified (roleplaying.Character)
 func parseMetaType(content string, idx int) (types.MetaType, int, error) {
 	metaType := types.MetaType{}
 
@@ -178,6 +188,21 @@ func parseMetaType(content string, idx int) (types.MetaType, int, error) {
 		return metaType, idx, err
 	}
 
+	if nextIdx < len(content) && string(content[nextIdx]) == PACKAGE_SEPARATOR {
+		nameToken, nameIdx, err := popIdentifier(content, nextIdx+1)
+		if err != nil || nameToken.Position != nextIdx+1 {
+			return metaType, nextIdx, formatParsingError(fmt.Sprintf("Expected type name after %s%s", token.Value, PACKAGE_SEPARATOR), content, nextIdx+1)
+		}
+
+		metaType.Position.Offset = token.Position
+		metaType.Length = nameIdx - token.Position
+		metaType.Package = token.Value
+		metaType.Name = nameToken.Value
+		return metaType, nameIdx, nil
+	}
+
+	metaType.Position.Offset = token.Position
+	metaType.Length = nextIdx - token.Position
 	metaType.Name = token.Value
 	return metaType, nextIdx, nil
 }
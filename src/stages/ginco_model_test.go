@@ -7,29 +7,6 @@ import (
 	"github.com/trudso/ginco/types"
 )
 
-func TestParseTrait(t *testing.T) {
-	testCases := []struct {
-		content             string
-		expectedTraitName   string
-		expectedNextIdx     int
-		expectedErrorValues []string
-	}{
-		{"", "", 0, []string{"Found EOF"}},
-		{"@", "", 1, []string{"No identifier found"}},
-		{"@Trait1", "Trait1", 7, nil},
-		{" @Trait1", "Trait1", 8, nil},
-		{"@Trait\nmodel Something {", "Trait", 6, nil},
-		{"Not a trait", "", 0, []string{"No symbol found"}},
-	}
-
-	for _, tc := range testCases {
-		trait, nextIdx, err := parseTrait(tc.content, 0)
-		assertErrorContains(t, err, tc.expectedErrorValues)
-		assert.Equal(t, tc.expectedTraitName, trait.Name)
-		assert.Equal(t, tc.expectedNextIdx, nextIdx)
-	}
-}
-
 func TestParseModelField(t *testing.T) {
 	testCases := []struct {
 		content             string
@@ -49,6 +26,7 @@ func TestParseModelField(t *testing.T) {
 		{"=* skills Skill", "skills", "Skill", types.Composition, types.Collection, 15, nil, nil},
 		{"@FieldTrait1\n=* skills Skill", "skills", "Skill", types.Composition, types.Collection, 28, []string {"FieldTrait1"}, nil},
 		{"@FieldTrait1\n@FieldTrait2\n@FieldTrait3\n=* skills Skill", "skills", "Skill", types.Composition, types.Collection, 54, []string {"FieldTrait1", "FieldTrait2", "FieldTrait3"}, nil}	,
+		{"=? oops\n=1 last string", "oops", "", types.Composition, types.ZeroOrOne, 7, nil, []string{"1:8", "No identifier found"}},
 	}
 
 	for _, tc := range testCases {
@@ -66,6 +44,32 @@ func TestParseModelField(t *testing.T) {
 	}
 }
 
+func TestParseMetaType(t *testing.T) {
+	testCases := []struct {
+		content             string
+		expectedPackage     string
+		expectedName        string
+		expectedNextIdx     int
+		expectedErrorValues []string
+	}{
+		{"", "", "", 0, []string{"No identifier found"}},
+		{"Character", "", "Character", 9, nil},
+		{"  Character\n", "", "Character", 11, nil},
+		{"roleplaying.Character", "roleplaying", "Character", 21, nil},
+		{"roleplaying.Character\n=1 id uuid", "roleplaying", "Character", 21, nil},
+		{"roleplaying.", "", "", 11, []string{"Expected type name after roleplaying."}},
+		{"roleplaying. Character", "", "", 11, []string{"Expected type name after roleplaying."}},
+	}
+
+	for _, tc := range testCases {
+		metaType, nextIdx, err := parseMetaType(tc.content, 0)
+		assertErrorContains(t, err, tc.expectedErrorValues)
+		assert.Equal(t, tc.expectedPackage, metaType.Package)
+		assert.Equal(t, tc.expectedName, metaType.Name)
+		assert.Equal(t, tc.expectedNextIdx, nextIdx)
+	}
+}
+
 func TestParseSimpleModel(t *testing.T) {
 	inputTest := `model Character {
 		fields {
@@ -84,3 +88,37 @@ func TestParseSimpleModel(t *testing.T) {
 	assert.Equal(t, len(inputTest), nextIdx)
 	assert.Equal(t, 5, len(model.Fields))
 }
+
+func TestParseModelFieldsResync(t *testing.T) {
+	inputTest := `fields {
+		=1 id uuid
+		=? oops
+		=1 last string
+	}`
+
+	fields, _, err := parseModelFields(inputTest, 0)
+	assertErrorContains(t, err, []string{"3:10", "No identifier found"})
+	assert.Equal(t, 2, len(fields))
+	assert.Equal(t, "last", fields[1].Name)
+	assert.Equal(t, "string", fields[1].Type.Name)
+}
+
+func TestParseModelWithoutBody(t *testing.T) {
+	testCases := []struct {
+		content             string
+		expectedModelName   string
+		expectedNextIdx     int
+		expectedErrorValues []string
+	}{
+		{"model Character", "Character", 15, []string{"Expected { after model Character"}},
+		{"model Character 5", "Character", 15, []string{"1:17", "Expected { after model Character"}},
+		{"model { }", "", 6, []string{"1:7", "No identifier found"}},
+	}
+
+	for _, tc := range testCases {
+		model, nextIdx, err := parseModel(tc.content, 0)
+		assertErrorContains(t, err, tc.expectedErrorValues)
+		assert.Equal(t, tc.expectedModelName, model.Name)
+		assert.Equal(t, tc.expectedNextIdx, nextIdx)
+	}
+}
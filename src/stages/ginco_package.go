@@ -1,16 +1,22 @@
 package stages
 
-import "github.com/trudso/ginco/types"
+import (
+	"fmt"
+
+	"github.com/trudso/ginco/types"
+)
 
 /*
 	package roleplaying {
 		@changeset
+		@audited
 		model A {
 		}
 		model B {
 		}
-		model C {
+		enumeration C {
 		}
+		scalar Email string
 	}
 */
 
@@ -28,6 +34,7 @@ func parsePackage(content string, idx int) (types.MetaPackage, int, error) {
 	}
 
 	pkg.Name = nameToken.Value
+	pkg.Position.Offset = firstValidTokenIndex(content, idx)
 
 	// content
 	scope, nextIdx, err := popScope(content, nextIdx)
@@ -35,40 +42,157 @@ func parsePackage(content string, idx int) (types.MetaPackage, int, error) {
 		return pkg, nextIdx, err
 	}
 
+	scopeStart := scopeContentStart(scope, nextIdx)
 	scopeIdx := 0
-	var trait *types.MetaTrait = nil
+	// traits preceding the next declaration
+	traits := []types.MetaTrait{}
+	diagnostics := types.Diagnostics{}
+
+	// resync continues at the next declaration after a failed one
+	resync := func(err error) {
+		diagnostics = collectError(diagnostics, err)
+		scopeIdx = nextLineStartingWith(scope.Value, max(scopeIdx, errorOffset(err, scopeIdx)), TRAIT_SYMBOL, MODEL, ENUM, ENUMERATION, SCALAR)
+		traits = nil
+	}
+
 	for {
 		token, _, err := popToken( scope.Value, scopeIdx )
 		if err != nil {
-			return pkg, scopeIdx, err
+			resync(err)
+			continue
 		}
 
 		if token.Type == TT_EOF {
+			for _, trait := range traits {
+				err := formatParsingError(fmt.Sprintf("Trait @%s is not followed by a model, enum or scalar", trait.Name), scope.Value, trait.Position.Offset)
+				diagnostics = collectError(diagnostics, err)
+			}
+
+			qualifyTypes(&pkg)
+			if len(diagnostics) > 0 {
+				return pkg, nextIdx, relocateError(diagnostics, scopeStart, content)
+			}
 			return pkg, nextIdx, nil
 		}
 
+		if token.Type == TT_COMMENT {
+			_, scopeIdx, _ = popComment(scope.Value, scopeIdx)
+			continue
+		}
+
 		if token.Type == TT_SYMBOL && token.Value == TRAIT_SYMBOL {
-			//var newTrait types.MetaTrait
-			newTrait, nextIdx, err := parseTrait(scope.Value, scopeIdx)
-			trait = &newTrait
+			trait, nextIdx, err := parseTrait(scope.Value, scopeIdx)
 			if err != nil {
-				return pkg, scopeIdx, err
+				resync(err)
+				continue
 			}
+			traits = append(traits, trait)
 			scopeIdx = nextIdx
+			continue
 		}
 
 		if token.Type == TT_IDENTIFIER && token.Value == MODEL {
 			model, nextIdx, err := parseModel(scope.Value, scopeIdx)
-			if err != nil {
-				return pkg, scopeIdx, err
-			}
-			if trait != nil {
-				model.Traits = append( model.Traits, *trait)
-				trait = nil
+			if err != nil && !isRecovered(err) {
+				resync(err)
+				continue
 			}
+			diagnostics = collectError(diagnostics, err)
+			model.Traits = append(model.Traits, traits...)
+			traits = nil
+			walkModelPositions(&model, shiftBy(scopeStart))
 
+			model.Package = pkg.Name
 			pkg.Models = append( pkg.Models, model )
 			scopeIdx = nextIdx
+			continue
+		}
+
+		if isEnumKeyword(token) {
+			enum, nextIdx, err := parseEnum(scope.Value, scopeIdx)
+			if err != nil && !isRecovered(err) {
+				resync(err)
+				continue
+			}
+			diagnostics = collectError(diagnostics, err)
+			enum.Traits = append(enum.Traits, traits...)
+			traits = nil
+			walkEnumPositions(&enum, shiftBy(scopeStart))
+
+			enum.Package = pkg.Name
+			pkg.Enums = append(pkg.Enums, enum)
+			scopeIdx = nextIdx
+			continue
+		}
+
+		if token.Type == TT_IDENTIFIER && token.Value == SCALAR {
+			scalar, nextIdx, err := parseScalar(scope.Value, scopeIdx)
+			if err != nil {
+				resync(err)
+				continue
+			}
+			scalar.Traits = append(scalar.Traits, traits...)
+			traits = nil
+			walkScalarPositions(&scalar, shiftBy(scopeStart))
+
+			pkg.Scalars = append(pkg.Scalars, scalar)
+			scopeIdx = nextIdx
+			continue
 		}
+
+		resync(formatParsingError(fmt.Sprintf("Unexpected %q in package %s", token.Value, pkg.Name), scope.Value, token.Position))
+	}
+}
+
+// qualifyTypes defaults the package of every unqualified field
+// type and trait type argument to the enclosing package. Built-in
+// primitives are left unqualified, unless the package declares a
+// type of the same name.
+func qualifyTypes(pkg *types.MetaPackage) {
+	declared := map[string]bool{}
+	for _, model := range pkg.Models {
+		declared[model.Name] = true
+	}
+	for _, enum := range pkg.Enums {
+		declared[enum.Name] = true
+	}
+	for _, scalar := range pkg.Scalars {
+		declared[scalar.Name] = true
+	}
+
+	qualify := func(metaType *types.MetaType) {
+		if metaType.Package != "" || (types.IsBuiltinPrimitive(metaType.Name) && !declared[metaType.Name]) {
+			return
+		}
+
+		metaType.Package = pkg.Name
+	}
+
+	qualifyTraits := func(traits []types.MetaTrait) {
+		for t := range traits {
+			for a := range traits[t].Arguments {
+				argument := &traits[t].Arguments[a]
+				if argument.Kind == types.IdentifierValue || argument.Kind == types.TypeValue {
+					qualify(&argument.Type)
+				}
+			}
+		}
+	}
+
+	for m := range pkg.Models {
+		model := &pkg.Models[m]
+		qualifyTraits(model.Traits)
+		for f := range model.Fields {
+			qualify(&model.Fields[f].Type)
+			qualifyTraits(model.Fields[f].Traits)
+		}
+	}
+
+	for e := range pkg.Enums {
+		qualifyTraits(pkg.Enums[e].Traits)
+	}
+
+	for s := range pkg.Scalars {
+		qualifyTraits(pkg.Scalars[s].Traits)
 	}
 }
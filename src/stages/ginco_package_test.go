@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/trudso/ginco/types"
 )
 
 func TestParseSimplePackage(t *testing.T) {
@@ -60,3 +61,122 @@ func TestParseMultiModelPackage(t *testing.T) {
 	assert.Equal(t, 2, len(pkg.Models))
 	assert.Equal(t, len(inputTest), nextIdx) 
 }
+
+func TestParsePackageWithEnums(t *testing.T) {
+	inputTest := `package roleplaying {
+		@changeset
+		model Character {
+			fields {
+				=1 id uuid
+				-1 type CharacterType
+			}
+		}
+
+		@stringBased
+		enumeration CharacterType {
+			literals {
+				player
+				boss
+				npc
+			}
+		}
+
+		enum Alignment {
+			literals {
+				good
+				evil
+			}
+		}
+	}`
+
+	pkg, nextIdx, err := parsePackage(inputTest, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, len(inputTest), nextIdx)
+	assert.Equal(t, 1, len(pkg.Models))
+	assert.Equal(t, 2, len(pkg.Enums))
+
+	assert.Equal(t, "CharacterType", pkg.Enums[0].Name)
+	assert.Equal(t, []string{"player", "boss", "npc"}, pkg.Enums[0].Literals)
+	assert.Equal(t, 1, len(pkg.Enums[0].Traits))
+	assert.Equal(t, "stringBased", pkg.Enums[0].Traits[0].Name)
+
+	assert.Equal(t, "Alignment", pkg.Enums[1].Name)
+	assert.Equal(t, 0, len(pkg.Enums[1].Traits))
+}
+
+func TestParsePackageQualifiesTypes(t *testing.T) {
+	inputTest := `package horror {
+		model Vampire {
+			fields {
+				=1 base roleplaying.Character
+				-1 clan Clan
+				=1 name string
+				=1 born date
+			}
+		}
+
+		scalar date string
+	}`
+
+	pkg, _, err := parsePackage(inputTest, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(pkg.Models[0].Fields))
+	assert.Equal(t, "roleplaying", pkg.Models[0].Fields[0].Type.Package)
+	assert.Equal(t, "Character", pkg.Models[0].Fields[0].Type.Name)
+	assert.Equal(t, "horror", pkg.Models[0].Fields[1].Type.Package)
+	assert.Equal(t, "Clan", pkg.Models[0].Fields[1].Type.Name)
+	assert.Equal(t, "", pkg.Models[0].Fields[2].Type.Package)
+	assert.Equal(t, "horror", pkg.Models[0].Fields[3].Type.Package)
+}
+
+func TestParsePackageWithMultipleTraits(t *testing.T) {
+	inputTest := `package roleplaying {
+		@changeset
+		@audited
+		# comments do not detach traits
+		@table(name="characters")
+		model Character {}
+
+		model Skill {}
+
+		@stringBased
+		@deprecated
+		enum CharacterType {}
+
+		@format("email")
+		scalar Email string
+	}`
+
+	pkg, _, err := parsePackage(inputTest, 0)
+	assert.NoError(t, err)
+
+	traitNames := func(traits []types.MetaTrait) []string {
+		names := []string{}
+		for _, trait := range traits {
+			names = append(names, trait.Name)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"changeset", "audited", "table"}, traitNames(pkg.Models[0].Traits))
+	assert.Equal(t, []string{}, traitNames(pkg.Models[1].Traits))
+	assert.Equal(t, []string{"stringBased", "deprecated"}, traitNames(pkg.Enums[0].Traits))
+	assert.Equal(t, []string{"format"}, traitNames(pkg.Scalars[0].Traits))
+}
+
+func TestParsePackageWithDanglingTraits(t *testing.T) {
+	inputTest := `package roleplaying {
+		model Character {}
+
+		@changeset
+		@audited
+	}`
+
+	pkg, _, err := parsePackage(inputTest, 0)
+	assertErrorContains(t, err, []string{
+		"4:3: Trait @changeset is not followed by a model, enum or scalar",
+		"5:3: Trait @audited is not followed by a model, enum or scalar",
+	})
+	assert.Equal(t, 1, len(pkg.Models))
+	assert.Equal(t, 0, len(pkg.Models[0].Traits))
+}
@@ -0,0 +1,104 @@
+package stages
+
+import (
+	"strings"
+
+	"github.com/trudso/ginco/types"
+)
+
+// Node positions are recorded as byte offsets relative to the content
+// handed to the parse function. Every parser parsing a nested scope
+// shifts the offsets of its children by the start of that scope, so the
+// offsets are absolute once they reach parseMetaFile.
+
+// scopeContentStart returns the offset of the first rune inside the scope
+// given the scope token and the index following it
+func scopeContentStart(scope Token, nextIdx int) int {
+	return nextIdx - 1 - len(scope.Value)
+}
+
+func walkTraitPositions(traits []types.MetaTrait, visit func(*types.Position)) {
+	for t := range traits {
+		visit(&traits[t].Position)
+		for a := range traits[t].Arguments {
+			argument := &traits[t].Arguments[a]
+			visit(&argument.Position)
+			if argument.Kind == types.IdentifierValue || argument.Kind == types.TypeValue {
+				visit(&argument.Type.Position)
+			}
+		}
+	}
+}
+
+func walkFieldPositions(fields []types.MetaModelField, visit func(*types.Position)) {
+	for f := range fields {
+		visit(&fields[f].Position)
+		visit(&fields[f].Type.Position)
+		walkTraitPositions(fields[f].Traits, visit)
+	}
+}
+
+func walkModelPositions(model *types.MetaModel, visit func(*types.Position)) {
+	visit(&model.Position)
+	walkTraitPositions(model.Traits, visit)
+	walkFieldPositions(model.Fields, visit)
+}
+
+func walkEnumPositions(enum *types.MetaEnum, visit func(*types.Position)) {
+	visit(&enum.Position)
+	walkTraitPositions(enum.Traits, visit)
+}
+
+func walkScalarPositions(scalar *types.MetaScalar, visit func(*types.Position)) {
+	visit(&scalar.Position)
+	walkTraitPositions(scalar.Traits, visit)
+}
+
+func walkPackagePositions(pkg *types.MetaPackage, visit func(*types.Position)) {
+	visit(&pkg.Position)
+	for m := range pkg.Models {
+		walkModelPositions(&pkg.Models[m], visit)
+	}
+
+	for e := range pkg.Enums {
+		walkEnumPositions(&pkg.Enums[e], visit)
+	}
+
+	for s := range pkg.Scalars {
+		walkScalarPositions(&pkg.Scalars[s], visit)
+	}
+}
+
+func shiftBy(by int) func(*types.Position) {
+	return func(position *types.Position) {
+		position.Offset += by
+	}
+}
+
+// locate fills in the line and column of a position from its offset
+func locate(position *types.Position, content string) {
+	idx := max(0, min(position.Offset, len(content)))
+
+	contentToIdx := content[:idx]
+	position.Line = strings.Count(contentToIdx, "\n") + 1
+	position.Column = idx - strings.LastIndex(contentToIdx, "\n")
+}
+
+func locatePackage(pkg *types.MetaPackage, content string) {
+	walkPackagePositions(pkg, func(position *types.Position) {
+		locate(position, content)
+	})
+}
+
+// setFile records the file every node of file was parsed from
+func setFile(file *types.MetaFile, path string) {
+	for i := range file.Imports {
+		file.Imports[i].Position.File = path
+	}
+
+	for p := range file.Packages {
+		walkPackagePositions(&file.Packages[p], func(position *types.Position) {
+			position.File = path
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package stages
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/trudso/ginco/types"
+)
+
+const (
+	SCALAR = "scalar"
+)
+
+/*
+	scalar Email string {
+		postgres VARCHAR(320)
+		typescript EmailAddress
+	}
+*/
+func parseScalar(content string, idx int) (types.MetaScalar, int, error) {
+	scalar := types.MetaScalar{}
+	_, nextIdx, err := popExpectedToken(content, idx, TT_IDENTIFIER, SCALAR)
+	if err != nil {
+		return scalar, idx, err
+	}
+
+	scalar.Position.Offset = firstValidTokenIndex(content, idx)
+
+	nameToken, nextIdx, err := popIdentifier(content, nextIdx)
+	if err != nil {
+		return scalar, nextIdx, err
+	}
+
+	scalar.Name = nameToken.Value
+
+	baseToken, nextIdx, err := popIdentifier(content, nextIdx)
+	if err != nil {
+		return scalar, nextIdx, err
+	}
+
+	scalar.Base = baseToken.Value
+
+	// the mappings are optional
+	scopeIdx := firstValidTokenIndex(content, nextIdx)
+	if scopeIdx == -1 || content[scopeIdx] != '{' {
+		return scalar, nextIdx, nil
+	}
+
+	scope, nextIdx, err := popScope(content, nextIdx)
+	if err != nil {
+		return scalar, nextIdx, err
+	}
+
+	mappings, err := parseScalarMappings(scope.Value)
+	if err != nil {
+		return scalar, nextIdx, relocateError(err, scopeContentStart(scope, nextIdx), content)
+	}
+
+	scalar.Mappings = mappings
+	return scalar, nextIdx, nil
+}
+
+// parseScalarMappings parses one "target type" mapping per line,
+// where the type is the rest of the line
+func parseScalarMappings(content string) (map[types.Target]string, error) {
+	mappings := map[types.Target]string{}
+	idx := 0
+	for !isEOF(content, idx) {
+		targetToken, nextIdx, err := popIdentifier(content, idx)
+		if err != nil {
+			return mappings, err
+		}
+
+		target := types.Target(targetToken.Value)
+		if _, found := mappings[target]; found {
+			return mappings, formatParsingError(fmt.Sprintf("Duplicate mapping for target %s", target), content, targetToken.Position)
+		}
+
+		lineEndIdx := nextIndexOf(content, "\n", nextIdx)
+		if lineEndIdx == -1 {
+			lineEndIdx = len(content)
+		}
+
+		mappedType := strings.TrimSpace(content[nextIdx:lineEndIdx])
+		if mappedType == "" {
+			return mappings, formatParsingError(fmt.Sprintf("Expected a type for target %s", target), content, targetToken.Position)
+		}
+
+		mappings[target] = mappedType
+		idx = lineEndIdx
+	}
+
+	return mappings, nil
+}
@@ -0,0 +1,39 @@
+package stages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trudso/ginco/types"
+)
+
+func TestParseScalar(t *testing.T) {
+	testCases := []struct {
+		content             string
+		expectedName        string
+		expectedBase        string
+		expectedMappings    map[types.Target]string
+		expectedNextIdx     int
+		expectedErrorValues []string
+	}{
+		{"scalar Email string", "Email", "string", nil, 19, nil},
+		{"scalar Email string\nmodel Person {}", "Email", "string", nil, 19, nil},
+		{`scalar Email string {
+			postgres VARCHAR(320)
+			go   mail.Address
+		}`, "Email", "string", map[types.Target]string{types.TargetPostgres: "VARCHAR(320)", types.TargetGo: "mail.Address"}, 71, nil},
+		{"scalar Email", "Email", "", nil, 12, []string{"No identifier found"}},
+		{"scalar Email string {\n go\n}", "Email", "string", nil, 27, []string{"Expected a type for target go"}},
+		{"scalar Email string {\n go string\n go Email\n}", "Email", "string", nil, 44, []string{"Duplicate mapping for target go"}},
+		{"model Email string", "", "", nil, 0, []string{`Expected value "scalar"`}},
+	}
+
+	for _, tc := range testCases {
+		scalar, nextIdx, err := parseScalar(tc.content, 0)
+		assertErrorContains(t, err, tc.expectedErrorValues)
+		assert.Equal(t, tc.expectedName, scalar.Name)
+		assert.Equal(t, tc.expectedBase, scalar.Base)
+		assert.Equal(t, tc.expectedMappings, scalar.Mappings)
+		assert.Equal(t, tc.expectedNextIdx, nextIdx)
+	}
+}
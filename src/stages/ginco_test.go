@@ -0,0 +1,206 @@
+package stages
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trudso/ginco/types"
+)
+
+func TestParseMetaFile(t *testing.T) {
+	testCases := []struct {
+		content              string
+		expectedPackageNames []string
+		expectedErrorValues  []string
+	}{
+		{"", nil, nil},
+		{"# only a comment", nil, nil},
+		{"package a {}", []string{"a"}, nil},
+		{`# roleplaying
+		package roleplaying {
+			# the main character
+			model Character {
+				fields {
+					=1 id uuid
+				}
+			}
+		}
+
+		# horror
+		package horror {}`, []string{"roleplaying", "horror"}, nil},
+		{"package a {} }", []string{"a"}, []string{"1:14", "Unexpected content at top level"}},
+		{"package a {}\nmodel B {}", []string{"a"}, []string{"2:1", `Unknown top level keyword "model"`}},
+		{"package a { something }", []string{"a"}, []string{`Unexpected "something" in package a`}},
+	}
+
+	for _, tc := range testCases {
+		file, _, err := parseMetaFile(tc.content, 0)
+		assertErrorContains(t, err, tc.expectedErrorValues)
+
+		packageNames := []string{}
+		for _, pkg := range file.Packages {
+			packageNames = append(packageNames, pkg.Name)
+		}
+		assert.Equal(t, len(tc.expectedPackageNames), len(packageNames))
+		for _, name := range tc.expectedPackageNames {
+			assert.Contains(t, packageNames, name)
+		}
+	}
+}
+
+func TestGincoMetaFileParserParse(t *testing.T) {
+	reader := strings.NewReader(`package roleplaying {
+		@changeset
+		model Character {
+			fields {
+				=1 id uuid
+				=* skills Skill
+			}
+		}
+
+		model Skill {
+			fields {
+				=1 name string
+			}
+		}
+	}`)
+
+	file, err := GincoMetaFileParser{}.Parse(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(file.Packages))
+	assert.Equal(t, "roleplaying", file.Packages[0].Name)
+	assert.Equal(t, 2, len(file.Packages[0].Models))
+	assert.Equal(t, 2, len(file.Packages[0].Models[0].Fields))
+}
+
+func TestParseMetaFilePositions(t *testing.T) {
+	content := `# header
+package roleplaying {
+	@changeset
+	model Character {
+		fields {
+			=1 id uuid
+			@noChangeset
+			=? name string
+		}
+	}
+
+	enum CharacterType {}
+}`
+
+	file, _, err := parseMetaFile(content, 0)
+	assert.NoError(t, err)
+
+	pkg := file.Packages[0]
+	assert.Equal(t, types.Position{Line: 2, Column: 1, Offset: 9}, pkg.Position)
+	assert.Equal(t, types.Position{Line: 3, Column: 2, Offset: 32}, pkg.Models[0].Traits[0].Position)
+	assert.Equal(t, types.Position{Line: 4, Column: 2, Offset: 44}, pkg.Models[0].Position)
+	assert.Equal(t, types.Position{Line: 6, Column: 4, Offset: 76}, pkg.Models[0].Fields[0].Position)
+	assert.Equal(t, types.Position{Line: 6, Column: 10, Offset: 82}, pkg.Models[0].Fields[0].Type.Position)
+	assert.Equal(t, types.Position{Line: 7, Column: 4, Offset: 90}, pkg.Models[0].Fields[1].Traits[0].Position)
+	assert.Equal(t, types.Position{Line: 8, Column: 4, Offset: 106}, pkg.Models[0].Fields[1].Position)
+	assert.Equal(t, types.Position{Line: 12, Column: 2, Offset: 130}, pkg.Enums[0].Position)
+}
+
+func TestParseMetaFileErrorPositions(t *testing.T) {
+	testCases := []struct {
+		content          string
+		expectedPosition types.Position
+		expectedLength   int
+		expectedMessage  string
+	}{
+		{"package a {}\nmodel B {}", types.Position{Line: 2, Column: 1, Offset: 13}, 5, `Unknown top level keyword "model"`},
+		{`package roleplaying {
+	model Character {
+		fields {
+			=1 id uuid
+			=1 $name string
+		}
+	}
+}`, types.Position{Line: 5, Column: 7, Offset: 72}, 1, "No identifier found"},
+		{`package roleplaying {
+	enum CharacterType {
+		literals {
+			player
+			player
+		}
+	}
+}`, types.Position{Line: 5, Column: 4, Offset: 70}, 6, "duplicate literal found"},
+		{"package a {\n model X\n}", types.Position{Line: 2, Column: 9, Offset: 20}, 0, "Expected { after model X"},
+		{"package a {\n enum E\n}", types.Position{Line: 2, Column: 8, Offset: 19}, 0, "Expected { after enum E"},
+		{"package a { model { } }", types.Position{Line: 1, Column: 19, Offset: 18}, 1, "No identifier found"},
+		{"package a {\n model X 5\n}", types.Position{Line: 2, Column: 10, Offset: 21}, 1, "Expected { after model X"},
+	}
+
+	for _, tc := range testCases {
+		_, _, err := parseMetaFile(tc.content, 0)
+
+		var diagnostic types.Diagnostic
+		assert.True(t, errors.As(err, &diagnostic))
+		assert.Equal(t, types.CodeSyntax, diagnostic.Code)
+		assert.Equal(t, types.SeverityError, diagnostic.Severity)
+		assert.Equal(t, tc.expectedPosition, diagnostic.Position)
+		assert.Equal(t, tc.expectedLength, diagnostic.Length)
+		assert.Equal(t, tc.expectedMessage, diagnostic.Message)
+	}
+}
+
+func TestParseMetaFileRecovery(t *testing.T) {
+	content := `package roleplaying {
+	model Character {
+		fields {
+			=1 id uuid
+			=1 $name string
+			=? age number
+			=x type CharacterType
+			=* skills Skill
+		}
+	}
+
+	something unexpected
+
+	@changeset
+	model Skill {
+		fields {
+			=1 name string
+		}
+	}
+
+	enum CharacterType {
+		literals {
+			player
+			$boss
+			player
+			npc
+		}
+	}
+}
+
+garbage
+
+package horror {
+	model Vampire {}
+}`
+
+	file, _, err := parseMetaFile(content, 0)
+
+	var diagnostics types.Diagnostics
+	assert.True(t, errors.As(err, &diagnostics))
+
+	positions := []string{}
+	for _, diagnostic := range diagnostics {
+		positions = append(positions, diagnostic.Position.String())
+	}
+	assert.Equal(t, []string{"5:7", "7:5", "12:2", "24:4", "25:4", "31:1"}, positions)
+
+	assert.Equal(t, 2, len(file.Packages))
+	roleplaying := file.Packages[0]
+	assert.Equal(t, 2, len(roleplaying.Models))
+	assert.Equal(t, 3, len(roleplaying.Models[0].Fields))
+	assert.Equal(t, "changeset", roleplaying.Models[1].Traits[0].Name)
+	assert.Equal(t, []string{"player", "npc"}, roleplaying.Enums[0].Literals)
+	assert.Equal(t, "horror", file.Packages[1].Name)
+	assert.Equal(t, "Vampire", file.Packages[1].Models[0].Name)
+}
@@ -0,0 +1,163 @@
+package stages
+
+import (
+	"fmt"
+	"unicode"
+
+	"github.com/trudso/ginco/types"
+)
+
+const (
+	ARGUMENTS_START     = "("
+	ARGUMENTS_END       = ")"
+	ARGUMENT_SEPARATOR  = ","
+	ARGUMENT_ASSIGNMENT = "="
+
+	TRUE  = "true"
+	FALSE = "false"
+)
+
+/*
+	@changeset
+	@maxLength(64)
+	@table(name="characters", temporary=false)
+	@inherits(roleplaying.Character)
+*/
+func parseTrait(content string, idx int) (types.MetaTrait, int, error) {
+	trait := types.MetaTrait{}
+	symbolToken, nextIdx, err := popSymbol(content, idx)
+	if err != nil {
+		return trait, nextIdx, err
+	}
+
+	if symbolToken.Value != TRAIT_SYMBOL {
+		return trait, idx, formatParsingError("No trait found", content, symbolToken.Position)
+	}
+
+	identifier, nextIdx, err := popIdentifier(content, nextIdx)
+	if err != nil {
+		return trait, nextIdx, err
+	}
+
+	trait.Name = identifier.Value
+	trait.Position.Offset = symbolToken.Position
+
+	// the arguments are optional and must follow the name directly
+	if nextIdx >= len(content) || string(content[nextIdx]) != ARGUMENTS_START {
+		return trait, nextIdx, nil
+	}
+
+	arguments, nextIdx, err := parseTraitArguments(content, nextIdx)
+	if err != nil {
+		return trait, nextIdx, err
+	}
+
+	trait.Arguments = arguments
+	return trait, nextIdx, nil
+}
+
+func parseTraitArguments(content string, idx int) ([]types.MetaTraitArgument, int, error) {
+	arguments := []types.MetaTraitArgument{}
+	_, nextIdx, err := popSingleRune(content, idx)
+	if err != nil {
+		return arguments, idx, err
+	}
+
+	if token, closeIdx, err := popSingleRune(content, nextIdx); err == nil && token.Value == ARGUMENTS_END {
+		return arguments, closeIdx, nil
+	}
+
+	for {
+		argument, argumentNextIdx, err := parseTraitArgument(content, nextIdx)
+		if err != nil {
+			return arguments, nextIdx, err
+		}
+
+		if argument.Name != "" {
+			if _, found := (types.MetaTrait{Arguments: arguments}).Argument(argument.Name); found {
+				return arguments, nextIdx, formatParsingError(fmt.Sprintf("Duplicate trait argument %s", argument.Name), content, argument.Position.Offset)
+			}
+		}
+
+		arguments = append(arguments, argument)
+
+		separator, separatorNextIdx, err := popSingleRune(content, argumentNextIdx)
+		if err != nil || (separator.Value != ARGUMENT_SEPARATOR && separator.Value != ARGUMENTS_END) {
+			return arguments, argumentNextIdx, formatParsingError(fmt.Sprintf("Expected %s or %s after trait argument", ARGUMENT_SEPARATOR, ARGUMENTS_END), content, firstValidTokenIndex(content, argumentNextIdx))
+		}
+
+		nextIdx = separatorNextIdx
+		if separator.Value == ARGUMENTS_END {
+			return arguments, nextIdx, nil
+		}
+	}
+}
+
+// parseTraitArgument parses a positional (64) or named (name="characters") argument
+func parseTraitArgument(content string, idx int) (types.MetaTraitArgument, int, error) {
+	valueIdx := firstValidTokenIndex(content, idx)
+	if valueIdx == -1 {
+		return types.MetaTraitArgument{}, idx, formatParsingError("Expected a trait argument", content, idx)
+	}
+
+	name := ""
+	if unicode.IsLetter(rune(content[valueIdx])) {
+		nameToken, nameNextIdx, _ := popIdentifier(content, valueIdx)
+		assignment, assignmentNextIdx, err := popSingleRune(content, nameNextIdx)
+		if err == nil && assignment.Value == ARGUMENT_ASSIGNMENT {
+			name = nameToken.Value
+			idx = assignmentNextIdx
+		}
+	}
+
+	argument, nextIdx, err := parseTraitValue(content, idx)
+	if err != nil {
+		return argument, nextIdx, err
+	}
+
+	argument.Name = name
+	argument.Position.Offset = valueIdx
+	return argument, nextIdx, nil
+}
+
+func parseTraitValue(content string, idx int) (types.MetaTraitArgument, int, error) {
+	argument := types.MetaTraitArgument{}
+	valueIdx := firstValidTokenIndex(content, idx)
+	if valueIdx == -1 {
+		return argument, idx, formatParsingError("Expected a trait argument value", content, idx)
+	}
+
+	r := rune(content[valueIdx])
+	switch {
+	case r == '"':
+		token, nextIdx, err := popString(content, valueIdx)
+		argument.Kind = types.StringValue
+		argument.Value = token.Value
+		return argument, nextIdx, err
+	case r == '-' || unicode.IsDigit(r):
+		token, nextIdx, err := popDecimal(content, valueIdx)
+		argument.Kind = types.NumberValue
+		argument.Value = token.Value
+		return argument, nextIdx, err
+	case unicode.IsLetter(r):
+		metaType, nextIdx, err := parseMetaType(content, valueIdx)
+		if err != nil {
+			return argument, nextIdx, err
+		}
+
+		argument.Value = formatMetaType(metaType)
+		switch {
+		case metaType.Package != "":
+			argument.Kind = types.TypeValue
+			argument.Type = metaType
+		case metaType.Name == TRUE || metaType.Name == FALSE:
+			argument.Kind = types.BoolValue
+		default:
+			argument.Kind = types.IdentifierValue
+			argument.Type = metaType
+		}
+		return argument, nextIdx, nil
+	}
+
+	return argument, valueIdx, formatParsingError("Expected a string, number, boolean, identifier or type as trait argument value", content, valueIdx)
+}
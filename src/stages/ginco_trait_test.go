@@ -0,0 +1,96 @@
+package stages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trudso/ginco/types"
+)
+
+func TestParseTrait(t *testing.T) {
+	testCases := []struct {
+		content             string
+		expectedTraitName   string
+		expectedNextIdx     int
+		expectedErrorValues []string
+	}{
+		{"", "", 0, []string{"Found EOF"}},
+		{"@", "", 1, []string{"No identifier found"}},
+		{"@Trait1", "Trait1", 7, nil},
+		{" @Trait1", "Trait1", 8, nil},
+		{"@Trait\nmodel Something {", "Trait", 6, nil},
+		{"Not a trait", "", 0, []string{"No symbol found"}},
+	}
+
+	for _, tc := range testCases {
+		trait, nextIdx, err := parseTrait(tc.content, 0)
+		assertErrorContains(t, err, tc.expectedErrorValues)
+		assert.Equal(t, tc.expectedTraitName, trait.Name)
+		assert.Equal(t, tc.expectedNextIdx, nextIdx)
+	}
+}
+
+func TestParseTraitArguments(t *testing.T) {
+	testCases := []struct {
+		content             string
+		expectedArguments   []types.MetaTraitArgument
+		expectedNextIdx     int
+		expectedErrorValues []string
+	}{
+		{"@changeset()", []types.MetaTraitArgument{}, 12, nil},
+		{"@changeset\n(", nil, 10, nil},
+		{"@maxLength(64)", []types.MetaTraitArgument{
+			{Position: types.Position{Offset: 11}, Kind: types.NumberValue, Value: "64"},
+		}, 14, nil},
+		{"@range(-1.5, 10)", []types.MetaTraitArgument{
+			{Position: types.Position{Offset: 7}, Kind: types.NumberValue, Value: "-1.5"},
+			{Position: types.Position{Offset: 13}, Kind: types.NumberValue, Value: "10"},
+		}, 16, nil},
+		{`@table(name="characters", temporary = false)`, []types.MetaTraitArgument{
+			{Position: types.Position{Offset: 7}, Name: "name", Kind: types.StringValue, Value: "characters"},
+			{Position: types.Position{Offset: 26}, Name: "temporary", Kind: types.BoolValue, Value: "false"},
+		}, 44, nil},
+		{`@pattern("\"[a-z]\\d\"")`, []types.MetaTraitArgument{
+			{Position: types.Position{Offset: 9}, Kind: types.StringValue, Value: `"[a-z]\d"`},
+		}, 24, nil},
+		{"@inherits(roleplaying.Character)", []types.MetaTraitArgument{
+			{Position: types.Position{Offset: 10}, Kind: types.TypeValue, Value: "roleplaying.Character", Type: types.MetaType{Position: types.Position{Offset: 10}, Length: 21, Package: "roleplaying", Name: "Character"}},
+		}, 32, nil},
+		{"@index(\n\tname,\n\tunique=true\n)", []types.MetaTraitArgument{
+			{Position: types.Position{Offset: 9}, Kind: types.IdentifierValue, Value: "name", Type: types.MetaType{Position: types.Position{Offset: 9}, Length: 4, Name: "name"}},
+			{Position: types.Position{Offset: 16}, Name: "unique", Kind: types.BoolValue, Value: "true"},
+		}, 29, nil},
+		{"@maxLength(64", nil, 13, []string{"Expected , or ) after trait argument"}},
+		{"@maxLength(64 65)", nil, 13, []string{"1:15", "Expected , or ) after trait argument"}},
+		{"@table(name=)", nil, 7, []string{"Expected a string, number, boolean, identifier or type"}},
+		{`@table(name="characters`, nil, 7, []string{"Unterminated string"}},
+		{`@table(name="a", name="b")`, nil, 16, []string{"Duplicate trait argument name"}},
+	}
+
+	for _, tc := range testCases {
+		trait, nextIdx, err := parseTrait(tc.content, 0)
+		assertErrorContains(t, err, tc.expectedErrorValues)
+		assert.Equal(t, tc.expectedNextIdx, nextIdx, tc.content)
+		if err == nil {
+			assert.Equal(t, tc.expectedArguments, trait.Arguments, tc.content)
+		}
+	}
+}
+
+func TestMetaTraitArgumentLookup(t *testing.T) {
+	trait, _, err := parseTrait(`@table("characters", schema="game", 3)`, 0)
+	assert.NoError(t, err)
+
+	argument, found := trait.Argument("schema")
+	assert.True(t, found)
+	assert.Equal(t, "game", argument.Value)
+
+	argument, found = trait.Positional(1)
+	assert.True(t, found)
+	assert.Equal(t, "3", argument.Value)
+
+	_, found = trait.Positional(2)
+	assert.False(t, found)
+	_, found = trait.Argument("name")
+	assert.False(t, found)
+}
@@ -0,0 +1,197 @@
+package stages
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path"
+	"text/template"
+
+	"github.com/trudso/ginco/types"
+)
+
+/*
+	package roleplaying {
+		enum Clan {
+			literals {
+				Brujah
+				Ventrue
+			}
+		}
+	}
+
+generates roleplaying/clan.go
+
+	package roleplaying
+
+	type Clan int
+
+	const (
+		ClanBrujah Clan = iota
+		ClanVentrue
+	)
+
+along with String, ParseClan, ClanValues, IsValid, MarshalText and UnmarshalText
+*/
+
+// GoEnumEmitter generates a Go type per enum with a constant per literal,
+// one Go package per ginco package
+type GoEnumEmitter struct {
+	// StringBased generates string based enums holding the literal instead
+	// of int based enums holding its index
+	StringBased bool
+	// Packages overrides the Go package name of a ginco package, which
+	// defaults to the lower cased package name
+	Packages map[string]string
+}
+
+func (self GoEnumEmitter) Name() string {
+	return "go-enum"
+}
+
+// GenerateFile generates a Go file for every enum of file
+func (self GoEnumEmitter) GenerateFile(file types.MetaFile) ([]ModelEmitterResult, error) {
+	results := []ModelEmitterResult{}
+	for _, pkg := range file.Packages {
+		for _, enum := range pkg.Enums {
+			result, err := self.generateEnum(pkg.Name, enum)
+			if err != nil {
+				return results, err
+			}
+
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+type goEnumLiteral struct {
+	Constant string
+	Value    string
+}
+
+func (self GoEnumEmitter) generateEnum(pkg string, enum types.MetaEnum) (ModelEmitterResult, error) {
+	data := struct {
+		Header      string
+		Package     string
+		Source      string
+		Type        string
+		StringBased bool
+		Literals    []goEnumLiteral
+	}{
+		Header:      GENERATED_HEADER,
+		Package:     goPackage(self.Packages, pkg),
+		Source:      qualifiedName(pkg, enum.Name),
+		Type:        goName(enum.Name),
+		StringBased: self.StringBased,
+	}
+
+	for _, literal := range enum.Literals {
+		data.Literals = append(data.Literals, goEnumLiteral{Constant: data.Type + goName(literal), Value: literal})
+	}
+
+	source := bytes.Buffer{}
+	if err := goEnumTemplate.Execute(&source, data); err != nil {
+		return ModelEmitterResult{}, fmt.Errorf("enum %s: %w", data.Source, err)
+	}
+
+	content, err := format.Source(source.Bytes())
+	if err != nil {
+		return ModelEmitterResult{}, fmt.Errorf("formatting %s: %w", data.Source, err)
+	}
+
+	return ModelEmitterResult{Path: path.Join(data.Package, snakeCase(enum.Name)+".go"), Content: string(content)}, nil
+}
+
+var goEnumTemplate = template.Must(template.New("enum").Parse(`{{.Header}}
+
+package {{.Package}}
+
+import "fmt"
+
+// {{.Type}} is generated from the enum {{.Source}}
+{{- if .StringBased}}
+type {{.Type}} string
+
+const (
+{{- range .Literals}}
+	{{.Constant}} {{$.Type}} = {{printf "%q" .Value}}
+{{- end}}
+)
+{{- else}}
+type {{.Type}} int
+
+const (
+{{- range $i, $literal := .Literals}}
+	{{$literal.Constant}}{{if eq $i 0}} {{$.Type}} = iota{{end}}
+{{- end}}
+)
+{{- end}}
+
+// IsValid reports whether self is one of the declared literals
+func (self {{.Type}}) IsValid() bool {
+{{- if .Literals}}
+	switch self {
+	case {{range $i, $literal := .Literals}}{{if $i}}, {{end}}{{$literal.Constant}}{{end}}:
+		return true
+	}
+
+{{end -}}
+	return false
+}
+
+func (self {{.Type}}) String() string {
+{{- if .StringBased}}
+	return string(self)
+{{- else}}
+	switch self {
+{{- range .Literals}}
+	case {{.Constant}}:
+		return {{printf "%q" .Value}}
+{{- end}}
+	}
+
+	return fmt.Sprintf("{{.Type}}(%d)", int(self))
+{{- end}}
+}
+
+// Parse{{.Type}} returns the {{.Type}} named value
+func Parse{{.Type}}(value string) ({{.Type}}, error) {
+	switch value {
+{{- range .Literals}}
+	case {{printf "%q" .Value}}:
+		return {{.Constant}}, nil
+{{- end}}
+	}
+
+	return {{if .StringBased}}""{{else}}0{{end}}, fmt.Errorf("invalid {{.Type}} %q", value)
+}
+
+// {{.Type}}Values returns every {{.Type}} in declaration order
+func {{.Type}}Values() []{{.Type}} {
+	return []{{.Type}}{
+{{- range .Literals}}
+		{{.Constant}},
+{{- end}}
+	}
+}
+
+func (self {{.Type}}) MarshalText() ([]byte, error) {
+	if !self.IsValid() {
+		return nil, fmt.Errorf("invalid {{.Type}} %s", self)
+	}
+
+	return []byte(self.String()), nil
+}
+
+func (self *{{.Type}}) UnmarshalText(text []byte) error {
+	value, err := Parse{{.Type}}(string(text))
+	if err != nil {
+		return err
+	}
+
+	*self = value
+	return nil
+}
+`))
@@ -0,0 +1,115 @@
+package stages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const CLAN_GINCO = `package roleplaying {
+	enum Clan {
+		literals {
+			Brujah
+			Ventrue
+		}
+	}
+}`
+
+func TestGoEnumEmitter(t *testing.T) {
+	file := parseGinco(t, CLAN_GINCO)
+
+	results, err := GoEnumEmitter{}.GenerateFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"roleplaying/clan.go"}, resultPaths(results))
+	assert.Equal(t, `// Code generated by ginco. DO NOT EDIT.
+
+package roleplaying
+
+import "fmt"
+
+// Clan is generated from the enum roleplaying.Clan
+type Clan int
+
+const (
+	ClanBrujah Clan = iota
+	ClanVentrue
+)
+
+// IsValid reports whether self is one of the declared literals
+func (self Clan) IsValid() bool {
+	switch self {
+	case ClanBrujah, ClanVentrue:
+		return true
+	}
+
+	return false
+}
+
+func (self Clan) String() string {
+	switch self {
+	case ClanBrujah:
+		return "Brujah"
+	case ClanVentrue:
+		return "Ventrue"
+	}
+
+	return fmt.Sprintf("Clan(%d)", int(self))
+}
+
+// ParseClan returns the Clan named value
+func ParseClan(value string) (Clan, error) {
+	switch value {
+	case "Brujah":
+		return ClanBrujah, nil
+	case "Ventrue":
+		return ClanVentrue, nil
+	}
+
+	return 0, fmt.Errorf("invalid Clan %q", value)
+}
+
+// ClanValues returns every Clan in declaration order
+func ClanValues() []Clan {
+	return []Clan{
+		ClanBrujah,
+		ClanVentrue,
+	}
+}
+
+func (self Clan) MarshalText() ([]byte, error) {
+	if !self.IsValid() {
+		return nil, fmt.Errorf("invalid Clan %s", self)
+	}
+
+	return []byte(self.String()), nil
+}
+
+func (self *Clan) UnmarshalText(text []byte) error {
+	value, err := ParseClan(string(text))
+	if err != nil {
+		return err
+	}
+
+	*self = value
+	return nil
+}
+`, results[0].Content)
+}
+
+func TestGoEnumEmitterStringBased(t *testing.T) {
+	file := parseGinco(t, CLAN_GINCO)
+
+	results, err := GoEnumEmitter{StringBased: true, Packages: map[string]string{"roleplaying": "rp"}}.GenerateFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"rp/clan.go"}, resultPaths(results))
+	assert.Contains(t, results[0].Content, `type Clan string
+
+const (
+	ClanBrujah  Clan = "Brujah"
+	ClanVentrue Clan = "Ventrue"
+)`)
+	assert.Contains(t, results[0].Content, `func (self Clan) String() string {
+	return string(self)
+}`)
+	assert.Contains(t, results[0].Content, `return "", fmt.Errorf("invalid Clan %q", value)`)
+}
@@ -0,0 +1,238 @@
+package stages
+
+import (
+	"fmt"
+	"go/format"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/trudso/ginco/types"
+)
+
+const (
+	GENERATED_HEADER = "// Code generated by ginco. DO NOT EDIT."
+)
+
+/*
+	package roleplaying {
+		model Character {
+			fields {
+				=1 id uuid
+				-* items Item
+			}
+		}
+	}
+
+generates roleplaying/character.go
+
+	package roleplaying
+
+	type Character struct {
+		ID    string  `json:"id"`
+		Items []*Item `json:"items"`
+	}
+*/
+
+// GoStructEmitter generates a Go struct per model, one Go package per
+// ginco package. Field types follow the cardinality and ownership:
+//   - ZeroOrOne fields are pointers
+//   - Collection fields are slices
+//   - aggregated models are referenced through pointers, composed models
+//     are embedded as values unless they embed the model themselves
+type GoStructEmitter struct {
+	// Module is the import path the generated packages live under. It is
+	// required when a model references a model of another package.
+	Module string
+	// Packages overrides the Go package name of a ginco package, which
+	// defaults to the lower cased package name
+	Packages map[string]string
+
+	resolver typeResolver
+}
+
+// NewGoStructEmitter creates an emitter knowing the scalars and enums of file
+func NewGoStructEmitter(file types.MetaFile) (GoStructEmitter, error) {
+	return GoStructEmitter{}.forFile(file)
+}
+
+func (self GoStructEmitter) Name() string {
+	return "go"
+}
+
+func (self GoStructEmitter) forFile(file types.MetaFile) (GoStructEmitter, error) {
+	resolver, err := newTypeResolver(file)
+	self.resolver = resolver
+	return self, err
+}
+
+// GenerateFile generates a struct for every model of file
+func (self GoStructEmitter) GenerateFile(file types.MetaFile) ([]ModelEmitterResult, error) {
+	emitter, err := self.forFile(file)
+	if err != nil {
+		return []ModelEmitterResult{}, err
+	}
+
+	return generateModels(file, emitter)
+}
+
+func (self GoStructEmitter) Generate(model types.MetaModel) ([]ModelEmitterResult, error) {
+	if err := self.resolver.resolving("create the emitter with NewGoStructEmitter"); err != nil {
+		return []ModelEmitterResult{}, err
+	}
+
+	pkg := self.packageName(model.Package)
+	imports := map[string]bool{}
+	fields := strings.Builder{}
+	for _, field := range model.Fields {
+		fieldType, err := self.fieldType(model, field, imports)
+		if err != nil {
+			return []ModelEmitterResult{}, err
+		}
+
+		tag := field.Name
+		if field.Cardinality == types.ZeroOrOne {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&fields, "\t%s %s `json:%q`\n", goName(field.Name), fieldType, tag)
+	}
+
+	source := strings.Builder{}
+	fmt.Fprintf(&source, "%s\n\npackage %s\n\n", GENERATED_HEADER, pkg)
+	writeGoImports(&source, imports)
+	fmt.Fprintf(&source, "// %s is generated from the model %s\n", goName(model.Name), qualifiedName(model.Package, model.Name))
+	fmt.Fprintf(&source, "type %s struct {\n%s}\n", goName(model.Name), fields.String())
+
+	content, err := format.Source([]byte(source.String()))
+	if err != nil {
+		return []ModelEmitterResult{}, fmt.Errorf("formatting %s: %w", model.Name, err)
+	}
+
+	return []ModelEmitterResult{
+		{Path: path.Join(pkg, snakeCase(model.Name)+".go"), Content: string(content)},
+	}, nil
+}
+
+func (self GoStructEmitter) packageName(pkg string) string {
+	return goPackage(self.Packages, pkg)
+}
+
+// goPackage returns the Go package name of pkg, unless overridden in packages
+func goPackage(packages map[string]string, pkg string) string {
+	if name, found := packages[pkg]; found {
+		return name
+	}
+
+	return goPackageName(pkg)
+}
+
+// fieldType returns the Go type of field, adding the packages it needs to imports
+func (self GoStructEmitter) fieldType(model types.MetaModel, field types.MetaModelField, imports map[string]bool) (string, error) {
+	goType, isModel, err := self.typeName(model.Package, field.Type, imports)
+	if err != nil {
+		return "", fmt.Errorf("field %s: %w", field.Name, err)
+	}
+
+	aggregated := isModel && field.Ownership == types.Aggregation
+	switch field.Cardinality {
+	case types.Collection:
+		if aggregated {
+			return "[]*" + goType, nil
+		}
+		return "[]" + goType, nil
+	case types.ZeroOrOne:
+		// slices are already nil when absent
+		if strings.HasPrefix(goType, "[]") {
+			return goType, nil
+		}
+		return "*" + goType, nil
+	default:
+		// a struct can not contain itself, so a composed model embedding
+		// the model again is referenced through a pointer as well
+		if aggregated || (isModel && self.embeds(field.Type, qualifiedName(model.Package, model.Name), map[string]bool{})) {
+			return "*" + goType, nil
+		}
+		return goType, nil
+	}
+}
+
+// embeds tells whether the model metaType refers to is, or embeds as a
+// value, the model called name
+func (self GoStructEmitter) embeds(metaType types.MetaType, name string, visited map[string]bool) bool {
+	model, found := self.resolver.model(metaType)
+	if !found {
+		return false
+	}
+
+	qualified := qualifiedName(model.Package, model.Name)
+	if qualified == name {
+		return true
+	}
+	if visited[qualified] {
+		return false
+	}
+	visited[qualified] = true
+
+	for _, field := range model.Fields {
+		if field.Cardinality == types.One && field.Ownership == types.Composition && self.embeds(field.Type, name, visited) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// typeName returns the Go type of metaType and whether it is a model
+func (self GoStructEmitter) typeName(pkg string, metaType types.MetaType, imports map[string]bool) (string, bool, error) {
+	if mapping, found := self.resolver.mapPrimitive(metaType, types.TargetGo); found {
+		return goImportedType(mapping.Type, imports), false, nil
+	}
+
+	name := goName(metaType.Name)
+	isModel := self.resolver.kind(metaType) == modelKind
+	if metaType.Package == "" || metaType.Package == pkg {
+		return name, isModel, nil
+	}
+
+	if self.Module == "" {
+		return "", false, fmt.Errorf("%s is declared in another package, set the module to import it", formatMetaType(metaType))
+	}
+
+	other := self.packageName(metaType.Package)
+	imports[path.Join(self.Module, other)] = true
+	return other + "." + name, isModel, nil
+}
+
+// goImportedType adds the package of a mapped Go type to imports and
+// returns the type qualified by the package name, e.g.
+// "github.com/shopspring/decimal.Decimal" -> "decimal.Decimal"
+func goImportedType(goType string, imports map[string]bool) string {
+	name := strings.TrimLeft(goType, "[]*")
+	prefix := goType[:len(goType)-len(name)]
+	separator := strings.LastIndex(name, ".")
+	if separator < 0 {
+		return goType
+	}
+
+	importPath := name[:separator]
+	imports[importPath] = true
+	return prefix + path.Base(importPath) + name[separator:]
+}
+
+func writeGoImports(builder *strings.Builder, imports map[string]bool) {
+	if len(imports) == 0 {
+		return
+	}
+
+	paths := []string{}
+	for importPath := range imports {
+		paths = append(paths, importPath)
+	}
+	slices.Sort(paths)
+
+	builder.WriteString("import (\n")
+	for _, importPath := range paths {
+		fmt.Fprintf(builder, "\t%q\n", importPath)
+	}
+	builder.WriteString(")\n\n")
+}
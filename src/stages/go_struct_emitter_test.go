@@ -0,0 +1,209 @@
+package stages
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trudso/ginco/types"
+)
+
+func parseGinco(t *testing.T, content string) types.MetaFile {
+	file, err := GincoMetaFileParser{}.Parse(strings.NewReader(content))
+	assert.NoError(t, err)
+	assert.NoError(t, ValidateMetaFile(file))
+	return file
+}
+
+func TestGoStructEmitter(t *testing.T) {
+	file := parseGinco(t, `package roleplaying {
+	scalar Money decimal {
+		go github.com/shopspring/decimal.Decimal
+	}
+
+	enum Clan {
+		Brujah
+	}
+
+	model Item {
+		fields {
+			=1 name string
+		}
+	}
+
+	model Character {
+		fields {
+			=1 id uuid
+			=? nickname string
+			=1 createdAt datetime
+			=1 clan Clan
+			=* tags string
+			=? avatar bytes
+			=1 wealth Money
+			=1 weapon Item
+			-1 owner Item
+			-? spare Item
+			=* inventory Item
+			-* loot Item
+		}
+	}
+}
+
+package horror {
+	model Vampire {
+		fields {
+			-1 sire roleplaying.Character
+		}
+	}
+}`)
+
+	results, err := GoStructEmitter{Module: "example.com/models"}.GenerateFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"roleplaying/item.go", "roleplaying/character.go", "horror/vampire.go"}, resultPaths(results))
+	assert.Equal(t, `// Code generated by ginco. DO NOT EDIT.
+
+package roleplaying
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// Character is generated from the model roleplaying.Character
+type Character struct {
+	ID        string          `+"`json:\"id\"`"+`
+	Nickname  *string         `+"`json:\"nickname,omitempty\"`"+`
+	CreatedAt time.Time       `+"`json:\"createdAt\"`"+`
+	Clan      Clan            `+"`json:\"clan\"`"+`
+	Tags      []string        `+"`json:\"tags\"`"+`
+	Avatar    []byte          `+"`json:\"avatar,omitempty\"`"+`
+	Wealth    decimal.Decimal `+"`json:\"wealth\"`"+`
+	Weapon    Item            `+"`json:\"weapon\"`"+`
+	Owner     *Item           `+"`json:\"owner\"`"+`
+	Spare     *Item           `+"`json:\"spare,omitempty\"`"+`
+	Inventory []Item          `+"`json:\"inventory\"`"+`
+	Loot      []*Item         `+"`json:\"loot\"`"+`
+}
+`, results[1].Content)
+	assert.Equal(t, `// Code generated by ginco. DO NOT EDIT.
+
+package horror
+
+import (
+	"example.com/models/roleplaying"
+)
+
+// Vampire is generated from the model horror.Vampire
+type Vampire struct {
+	Sire *roleplaying.Character `+"`json:\"sire\"`"+`
+}
+`, results[2].Content)
+}
+
+func TestGoStructEmitterPackages(t *testing.T) {
+	file := parseGinco(t, `package roleplaying {
+	model Character {
+		fields {
+			=1 id uuid
+		}
+	}
+}`)
+
+	results, err := GoStructEmitter{Packages: map[string]string{"roleplaying": "rp"}}.GenerateFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"rp/character.go"}, resultPaths(results))
+	assert.Contains(t, results[0].Content, "package rp\n")
+}
+
+func TestGoStructEmitterWithoutModule(t *testing.T) {
+	file := parseGinco(t, `package roleplaying {
+	model Character {
+		fields {
+			=1 id uuid
+		}
+	}
+}
+
+package horror {
+	model Vampire {
+		fields {
+			-1 sire roleplaying.Character
+		}
+	}
+}`)
+
+	_, err := GoStructEmitter{}.GenerateFile(file)
+	assertErrorContains(t, err, []string{"field sire: roleplaying.Character is declared in another package, set the module to import it"})
+}
+
+func TestGoStructEmitterRequiresFile(t *testing.T) {
+	file := parseGinco(t, `package people {
+	scalar Email string
+
+	enum Role {
+		literals {
+			Admin
+		}
+	}
+
+	model Person {
+		fields {
+			=1 email Email
+			-1 role Role
+		}
+	}
+}`)
+	model := file.Packages[0].Models[0]
+
+	_, err := GoStructEmitter{}.Generate(model)
+	assertErrorContains(t, err, []string{"types of the model are unknown", "NewGoStructEmitter"})
+
+	emitter, err := NewGoStructEmitter(file)
+	assert.NoError(t, err)
+	results, err := emitter.Generate(model)
+	assert.NoError(t, err)
+	assert.Contains(t, results[0].Content, "Email string `json:\"email\"`")
+	assert.Contains(t, results[0].Content, "Role  Role   `json:\"role\"`")
+}
+
+func resultPaths(results []ModelEmitterResult) []string {
+	paths := []string{}
+	for _, result := range results {
+		paths = append(paths, result.Path)
+	}
+
+	return paths
+}
+
+func TestGoStructEmitterRecursiveComposition(t *testing.T) {
+	file := parseGinco(t, `package graph {
+	model Node {
+		fields {
+			=1 parent Node
+			=* children Node
+		}
+	}
+
+	model Left {
+		fields {
+			=1 right Right
+		}
+	}
+
+	model Right {
+		fields {
+			=1 left Left
+			=1 node Node
+		}
+	}
+}`)
+
+	results, err := GoStructEmitter{}.GenerateFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"graph/node.go", "graph/left.go", "graph/right.go"}, resultPaths(results))
+	assert.Contains(t, results[0].Content, "Parent   *Node  ")
+	assert.Contains(t, results[0].Content, "Children []Node ")
+	assert.Contains(t, results[1].Content, "Right *Right ")
+	assert.Contains(t, results[2].Content, "Left *Left ")
+	assert.Contains(t, results[2].Content, "Node Node ")
+}
@@ -0,0 +1,196 @@
+package stages
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/trudso/ginco/types"
+)
+
+const (
+	GRAPHQL_IGNORE_TRAIT = "graphqlIgnore"
+	GRAPHQL_SCHEMA_FILE  = "schema.graphql"
+	GRAPHQL_INPUT_SUFFIX = "Input"
+	GRAPHQL_ID           = "ID"
+)
+
+// graphqlScalars are the scalars built into GraphQL
+var graphqlScalars = []string{"ID", "String", "Int", "Float", "Boolean"}
+
+/*
+	package roleplaying {
+		model Character {
+			fields {
+				=1 id uuid
+				=? nickname string
+				=* tags string
+				-1 weapon Item
+				@graphqlIgnore
+				=1 secret string
+			}
+		}
+	}
+
+generates schema.graphql
+
+	type Character {
+	  id: ID!
+	  nickname: String
+	  tags: [String!]!
+	  weapon: Item!
+	}
+
+	input CharacterInput {
+	  id: ID!
+	  nickname: String
+	  tags: [String!]!
+	  weaponId: ID!
+	}
+*/
+
+// GraphQLEmitter generates a single GraphQL schema holding an object type
+// and an input type per model and an enum type per enum. Fields with
+// cardinality One are non-null and collections are lists. Input types
+// embed the input types of composed models and refer to aggregated models
+// by ID. Fields marked with @graphqlIgnore are left out.
+//
+// GraphQL has no namespaces, so type names must be unique across packages.
+type GraphQLEmitter struct {
+	resolver typeResolver
+}
+
+func (self GraphQLEmitter) Name() string {
+	return "graphql"
+}
+
+func (self GraphQLEmitter) GenerateFile(file types.MetaFile) ([]ModelEmitterResult, error) {
+	resolver, err := newTypeResolver(file)
+	if err != nil {
+		return []ModelEmitterResult{}, err
+	}
+	self.resolver = resolver
+
+	declaredIn := map[string]string{}
+	declare := func(pkg, name string) error {
+		if other, found := declaredIn[name]; found {
+			return fmt.Errorf("%s clashes with %s, GraphQL type names must be unique across packages", qualifiedName(pkg, name), qualifiedName(other, name))
+		}
+
+		declaredIn[name] = pkg
+		return nil
+	}
+
+	scalars := []string{}
+	declarations := []string{}
+	for _, pkg := range file.Packages {
+		for _, enum := range pkg.Enums {
+			if err := declare(pkg.Name, enum.Name); err != nil {
+				return []ModelEmitterResult{}, err
+			}
+
+			if len(enum.Literals) == 0 {
+				return []ModelEmitterResult{}, fmt.Errorf("enum %s has no literals, GraphQL enums need at least one value", qualifiedName(pkg.Name, enum.Name))
+			}
+
+			declarations = append(declarations, fmt.Sprintf("enum %s {\n  %s\n}\n", enum.Name, strings.Join(enum.Literals, "\n  ")))
+		}
+
+		for _, model := range pkg.Models {
+			if err := declare(pkg.Name, model.Name); err != nil {
+				return []ModelEmitterResult{}, err
+			}
+
+			declarations = append(declarations,
+				self.objectType(model, &scalars),
+				self.inputType(model, &scalars),
+			)
+		}
+	}
+
+	content := strings.Builder{}
+	content.WriteString("# Code generated by ginco. DO NOT EDIT.\n")
+	slices.Sort(scalars)
+	for _, scalar := range slices.Compact(scalars) {
+		fmt.Fprintf(&content, "\nscalar %s\n", scalar)
+	}
+
+	for _, declaration := range declarations {
+		content.WriteString("\n" + declaration)
+	}
+
+	return []ModelEmitterResult{{Path: GRAPHQL_SCHEMA_FILE, Content: content.String()}}, nil
+}
+
+func (self GraphQLEmitter) objectType(model types.MetaModel, scalars *[]string) string {
+	declaration := strings.Builder{}
+	fmt.Fprintf(&declaration, "type %s {\n", model.Name)
+	for _, field := range graphqlFields(model) {
+		fmt.Fprintf(&declaration, "  %s: %s\n", field.Name, graphqlType(self.typeName(field.Type, "", scalars), field.Cardinality))
+	}
+	declaration.WriteString("}\n")
+
+	return declaration.String()
+}
+
+func (self GraphQLEmitter) inputType(model types.MetaModel, scalars *[]string) string {
+	declaration := strings.Builder{}
+	fmt.Fprintf(&declaration, "input %s%s {\n", model.Name, GRAPHQL_INPUT_SUFFIX)
+	for _, field := range graphqlFields(model) {
+		name := field.Name
+		fieldType := self.typeName(field.Type, GRAPHQL_INPUT_SUFFIX, scalars)
+		if self.resolver.kind(field.Type) == modelKind && field.Ownership == types.Aggregation {
+			if field.Cardinality == types.Collection {
+				name = singularize(name) + "Ids"
+			} else {
+				name += "Id"
+			}
+			fieldType = GRAPHQL_ID
+		}
+
+		fmt.Fprintf(&declaration, "  %s: %s\n", name, graphqlType(fieldType, field.Cardinality))
+	}
+	declaration.WriteString("}\n")
+
+	return declaration.String()
+}
+
+// typeName returns the GraphQL type of metaType, appending suffix to
+// models and adding custom scalars to scalars
+func (self GraphQLEmitter) typeName(metaType types.MetaType, suffix string, scalars *[]string) string {
+	if mapping, found := self.resolver.mapPrimitive(metaType, types.TargetGraphQL); found {
+		if !slices.Contains(graphqlScalars, mapping.Type) {
+			*scalars = append(*scalars, mapping.Type)
+		}
+		return mapping.Type
+	}
+
+	if self.resolver.kind(metaType) == modelKind {
+		return metaType.Name + suffix
+	}
+
+	return metaType.Name
+}
+
+// graphqlFields returns the fields of model not marked with @graphqlIgnore
+func graphqlFields(model types.MetaModel) []types.MetaModelField {
+	fields := []types.MetaModelField{}
+	for _, field := range model.Fields {
+		if _, ignored := types.FindTrait(field.Traits, GRAPHQL_IGNORE_TRAIT); !ignored {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+func graphqlType(name string, cardinality types.Cardinality) string {
+	switch cardinality {
+	case types.One:
+		return name + "!"
+	case types.Collection:
+		return "[" + name + "!]!"
+	default:
+		return name
+	}
+}
@@ -0,0 +1,118 @@
+package stages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphQLEmitter(t *testing.T) {
+	file := parseGinco(t, `package roleplaying {
+	scalar Email string {
+		graphql Email
+	}
+
+	enum Clan {
+		literals {
+			Brujah
+			Ventrue
+		}
+	}
+
+	model Item {
+		fields {
+			=1 name string
+		}
+	}
+
+	model Character {
+		fields {
+			=1 id uuid
+			=? email Email
+			=1 clan Clan
+			=* tags string
+			=1 weapon Item
+			-? owner Item
+			-* loot Item
+			-* items Item
+			@graphqlIgnore
+			=1 secret string
+		}
+	}
+}`)
+
+	results, err := GraphQLEmitter{}.GenerateFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{GRAPHQL_SCHEMA_FILE}, resultPaths(results))
+	assert.Equal(t, `# Code generated by ginco. DO NOT EDIT.
+
+scalar Email
+
+enum Clan {
+  Brujah
+  Ventrue
+}
+
+type Item {
+  name: String!
+}
+
+input ItemInput {
+  name: String!
+}
+
+type Character {
+  id: ID!
+  email: Email
+  clan: Clan!
+  tags: [String!]!
+  weapon: Item!
+  owner: Item
+  loot: [Item!]!
+  items: [Item!]!
+}
+
+input CharacterInput {
+  id: ID!
+  email: Email
+  clan: Clan!
+  tags: [String!]!
+  weapon: ItemInput!
+  ownerId: ID
+  lootIds: [ID!]!
+  itemIds: [ID!]!
+}
+`, results[0].Content)
+}
+
+func TestGraphQLEmitterNameClash(t *testing.T) {
+	file := parseGinco(t, `package roleplaying {
+	model Character {
+		fields {
+			=1 id uuid
+		}
+	}
+}
+
+package horror {
+	enum Character {
+		literals {
+			Vampire
+		}
+	}
+}`)
+
+	_, err := GraphQLEmitter{}.GenerateFile(file)
+	assertErrorContains(t, err, []string{"horror.Character clashes with roleplaying.Character"})
+}
+
+func TestGraphQLEmitterEmptyEnum(t *testing.T) {
+	file := parseGinco(t, `package roleplaying {
+	enum Clan {
+		literals {}
+	}
+}`)
+
+	_, err := GraphQLEmitter{}.GenerateFile(file)
+	assertErrorContains(t, err, []string{"enum roleplaying.Clan has no literals"})
+}
@@ -0,0 +1,174 @@
+package stages
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/trudso/ginco/types"
+)
+
+const (
+	INHERITS_TRAIT = "inherits"
+)
+
+// uninheritedTraits identify the model they are declared on, so they are
+// not merged into derived models
+var uninheritedTraits = []string{INHERITS_TRAIT, TABLE_TRAIT}
+
+/*
+	@inherits(roleplaying.Character)
+	model Vampire {
+		fields {
+			-1 clan Clan
+		}
+	}
+*/
+
+// InheritanceTransformer flattens models inheriting from a base model
+// with @inherits. The fields and traits of the base are merged into the
+// derived model:
+//   - inherited fields come first, followed by the fields of the model
+//   - a field redeclared with the same type overrides the inherited field
+//   - a field redeclared with another type is a conflict
+//   - traits of the model override inherited traits of the same name
+//   - @inherits and @table are not inherited
+type InheritanceTransformer struct {
+	models map[string]types.MetaModel
+}
+
+func NewInheritanceTransformer(file types.MetaFile) InheritanceTransformer {
+	models := map[string]types.MetaModel{}
+	for _, pkg := range file.Packages {
+		for _, model := range pkg.Models {
+			models[qualifiedName(pkg.Name, model.Name)] = model
+		}
+	}
+
+	return InheritanceTransformer{models: models}
+}
+
+// TransformFile flattens every model of file inheriting from another
+func (self InheritanceTransformer) TransformFile(file types.MetaFile) (types.MetaFile, error) {
+	return TransformModels(file, []ModelTransformer{NewInheritanceTransformer(file)})
+}
+
+func (self InheritanceTransformer) Transform(model types.MetaModel) ([]types.MetaModel, error) {
+	flattened, err := self.flatten(model, []string{})
+	return []types.MetaModel{flattened}, err
+}
+
+func (self InheritanceTransformer) flatten(model types.MetaModel, chain []string) (types.MetaModel, error) {
+	name := qualifiedName(model.Package, model.Name)
+	if slices.Contains(chain, name) {
+		cycle := strings.Join(append(chain, name), " -> ")
+		return model, inheritanceError(types.CodeInheritanceCycle, model.Position, "Inheritance cycle detected: %s", cycle)
+	}
+	chain = append(chain, name)
+
+	inherits := []types.MetaTrait{}
+	for _, trait := range model.Traits {
+		if trait.Name == INHERITS_TRAIT {
+			inherits = append(inherits, trait)
+		}
+	}
+
+	if len(inherits) == 0 {
+		return model, nil
+	}
+
+	if len(inherits) > 1 {
+		return model, inheritanceError(types.CodeInvalidTrait, inherits[1].Position, "Model %s inherits from more than one model", name)
+	}
+
+	argument, found := inherits[0].Positional(0)
+	if !found || (argument.Kind != types.IdentifierValue && argument.Kind != types.TypeValue) {
+		diagnostic := inheritanceError(types.CodeInvalidTrait, inherits[0].Position, "@%s of model %s expects the base model as argument", INHERITS_TRAIT, name)
+		diagnostic.Hint = fmt.Sprintf("e.g. @%s(roleplaying.Character)", INHERITS_TRAIT)
+		return model, diagnostic
+	}
+
+	baseType := argument.Type
+	base, found := self.models[qualifiedName(baseType.Package, baseType.Name)]
+	if !found {
+		return model, inheritanceError(types.CodeUnresolvedType, baseType.Position, "Unresolved base model %s of model %s", formatMetaType(baseType), name)
+	}
+
+	base, err := self.flatten(base, chain)
+	if err != nil {
+		return model, err
+	}
+
+	fields, err := mergeInheritedFields(base, model)
+	if err != nil {
+		return model, err
+	}
+
+	derived := model
+	derived.Base = &baseType
+	derived.Fields = fields
+	derived.Traits = mergeInheritedTraits(base.Traits, model.Traits)
+	return derived, nil
+}
+
+func mergeInheritedFields(base, model types.MetaModel) ([]types.MetaModelField, error) {
+	fields := []types.MetaModelField{}
+	for _, field := range base.Fields {
+		field.Inherited = true
+		fields = append(fields, field)
+	}
+
+	diagnostics := types.Diagnostics{}
+	for _, field := range model.Fields {
+		idx := slices.IndexFunc(fields, func(inherited types.MetaModelField) bool {
+			return inherited.Name == field.Name
+		})
+
+		if idx == -1 {
+			fields = append(fields, field)
+			continue
+		}
+
+		inherited := fields[idx]
+		if inherited.Type.Package != field.Type.Package || inherited.Type.Name != field.Type.Name {
+			diagnostics = append(diagnostics, inheritanceError(types.CodeInheritanceConflict, field.Position,
+				"Field %s of model %s has type %s, but the field inherited from %s has type %s",
+				field.Name, qualifiedName(model.Package, model.Name), formatMetaType(field.Type), qualifiedName(base.Package, base.Name), formatMetaType(inherited.Type)))
+			continue
+		}
+
+		fields[idx] = field
+	}
+
+	return fields, diagnostics.Err()
+}
+
+// mergeInheritedTraits returns the inherited traits not overridden by
+// the model, followed by the traits of the model
+func mergeInheritedTraits(inherited, traits []types.MetaTrait) []types.MetaTrait {
+	merged := []types.MetaTrait{}
+	for _, trait := range inherited {
+		overridden := slices.ContainsFunc(traits, func(own types.MetaTrait) bool {
+			return own.Name == trait.Name
+		})
+
+		if !slices.Contains(uninheritedTraits, trait.Name) && !overridden {
+			merged = append(merged, trait)
+		}
+	}
+
+	return append(merged, traits...)
+}
+
+func inheritanceError(code string, position types.Position, format string, args ...any) types.Diagnostic {
+	return types.Diagnostic{
+		Severity: types.SeverityError,
+		Code:     code,
+		Message:  fmt.Sprintf(format, args...),
+		Position: position,
+	}
+}
+
+func qualifiedName(pkg, name string) string {
+	return formatMetaType(types.MetaType{Package: pkg, Name: name})
+}
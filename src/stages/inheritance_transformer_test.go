@@ -0,0 +1,144 @@
+package stages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trudso/ginco/types"
+)
+
+func findModel(file types.MetaFile, pkg, name string) types.MetaModel {
+	for _, p := range file.Packages {
+		for _, model := range p.Models {
+			if p.Name == pkg && model.Name == name {
+				return model
+			}
+		}
+	}
+
+	return types.MetaModel{}
+}
+
+func TestInheritanceTransformer(t *testing.T) {
+	file, _, err := parseMetaFile(`package roleplaying {
+	@changeset
+	@table(name="characters")
+	model Character {
+		fields {
+			=1 id uuid
+			=? name string
+		}
+	}
+}
+
+package horror {
+	@table(name="vampires")
+	@inherits(roleplaying.Character)
+	model Vampire {
+		fields {
+			=1 name string
+			-1 clan Clan
+		}
+	}
+
+	@inherits(Vampire)
+	model Elder {
+		fields {
+			=1 age number
+		}
+	}
+}`, 0)
+	assert.NoError(t, err)
+
+	transformer := NewInheritanceTransformer(file)
+
+	models, err := transformer.Transform(findModel(file, "roleplaying", "Character"))
+	assert.NoError(t, err)
+	assert.Nil(t, models[0].Base)
+	assert.Equal(t, 2, len(models[0].Fields))
+
+	models, err = transformer.Transform(findModel(file, "horror", "Elder"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(models))
+
+	elder := models[0]
+	assert.Equal(t, "horror", elder.Base.Package)
+	assert.Equal(t, "Vampire", elder.Base.Name)
+
+	fieldNames := []string{}
+	inherited := []bool{}
+	for _, field := range elder.Fields {
+		fieldNames = append(fieldNames, field.Name)
+		inherited = append(inherited, field.Inherited)
+	}
+	assert.Equal(t, []string{"id", "name", "clan", "age"}, fieldNames)
+	assert.Equal(t, []bool{true, true, true, false}, inherited)
+	assert.Equal(t, types.One, elder.Fields[1].Cardinality)
+
+	traitNames := []string{}
+	for _, trait := range elder.Traits {
+		traitNames = append(traitNames, trait.Name)
+	}
+	assert.Equal(t, []string{"changeset", "inherits"}, traitNames)
+
+	models, err = transformer.Transform(findModel(file, "horror", "Vampire"))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(models[0].Traits))
+	trait, _ := types.FindTrait(models[0].Traits, TABLE_TRAIT)
+	table, _ := trait.Argument("name")
+	assert.Equal(t, "vampires", table.Value)
+}
+
+func TestInheritanceTransformerErrors(t *testing.T) {
+	testCases := []struct {
+		content             string
+		model               string
+		expectedErrorValues []string
+	}{
+		{`package a {
+	@inherits(C)
+	model A {}
+	@inherits(A)
+	model B {}
+	@inherits(B)
+	model C {}
+}`, "A", []string{"3:2: Inheritance cycle detected: a.A -> a.C -> a.B -> a.A"}},
+		{`package a {
+	@inherits(Missing)
+	model A {}
+}`, "A", []string{"2:12: Unresolved base model a.Missing of model a.A"}},
+		{`package a {
+	@inherits("B")
+	model A {}
+}`, "A", []string{"2:2: @inherits of model a.A expects the base model as argument"}},
+		{`package a {
+	@inherits(B)
+	@inherits(C)
+	model A {}
+	model B {}
+	model C {}
+}`, "A", []string{"3:2: Model a.A inherits from more than one model"}},
+		{`package a {
+	model B {
+		fields {
+			=1 id uuid
+		}
+	}
+
+	@inherits(B)
+	model A {
+		fields {
+			=1 id string
+		}
+	}
+}`, "A", []string{"11:4: Field id of model a.A has type string, but the field inherited from a.B has type uuid"}},
+	}
+
+	for _, tc := range testCases {
+		file, _, err := parseMetaFile(tc.content, 0)
+		assert.NoError(t, err)
+
+		_, err = NewInheritanceTransformer(file).Transform(findModel(file, "a", tc.model))
+		assertErrorContains(t, err, tc.expectedErrorValues)
+	}
+}
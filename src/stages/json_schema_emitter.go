@@ -0,0 +1,241 @@
+package stages
+
+import (
+	"bytes"
+	"encoding/json"
+	"path"
+
+	"github.com/trudso/ginco/types"
+)
+
+const (
+	JSON_SCHEMA_DIALECT   = "https://json-schema.org/draft/2020-12/schema"
+	JSON_SCHEMA_EXTENSION = ".schema.json"
+)
+
+/*
+	package roleplaying {
+		model Character {
+			fields {
+				=1 id uuid
+				-* items Item
+			}
+		}
+	}
+
+generates roleplaying/Character.schema.json
+
+	{
+	  "$schema": "https://json-schema.org/draft/2020-12/schema",
+	  "title": "Character",
+	  "type": "object",
+	  "properties": {
+	    "id": { "type": "string", "format": "uuid" },
+	    "items": { "type": "array", "items": { "$ref": "Item.schema.json" } }
+	  },
+	  "required": ["id"],
+	  "additionalProperties": false
+	}
+*/
+
+// JSONSchemaEmitter generates a JSON Schema (draft 2020-12) per model and
+// enum. Fields with cardinality One are required and collections are
+// arrays. Models and enums are referenced relative to the referencing
+// schema, so the generated files must keep their directory layout.
+type JSONSchemaEmitter struct {
+	// BaseURI is prepended to the path of every schema to form its $id,
+	// e.g. "https://example.com/schemas". No $id is emitted when empty.
+	BaseURI string
+
+	resolver typeResolver
+}
+
+// NewJSONSchemaEmitter creates an emitter knowing the scalars and enums of file
+func NewJSONSchemaEmitter(file types.MetaFile) (JSONSchemaEmitter, error) {
+	return JSONSchemaEmitter{}.forFile(file)
+}
+
+func (self JSONSchemaEmitter) Name() string {
+	return "jsonschema"
+}
+
+func (self JSONSchemaEmitter) forFile(file types.MetaFile) (JSONSchemaEmitter, error) {
+	resolver, err := newTypeResolver(file)
+	self.resolver = resolver
+	return self, err
+}
+
+// GenerateFile generates a schema for every model and enum of file
+func (self JSONSchemaEmitter) GenerateFile(file types.MetaFile) ([]ModelEmitterResult, error) {
+	emitter, err := self.forFile(file)
+	if err != nil {
+		return []ModelEmitterResult{}, err
+	}
+
+	results, err := generateModels(file, emitter)
+	if err != nil {
+		return results, err
+	}
+
+	for _, pkg := range file.Packages {
+		for _, enum := range pkg.Enums {
+			schema := emitter.header(pkg.Name, enum.Name)
+			schema = append(schema, enumSchema(enum)...)
+
+			result, err := jsonResult(jsonSchemaPath(pkg.Name, enum.Name), schema)
+			if err != nil {
+				return results, err
+			}
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+func (self JSONSchemaEmitter) Generate(model types.MetaModel) ([]ModelEmitterResult, error) {
+	if err := self.resolver.resolving("create the emitter with NewJSONSchemaEmitter"); err != nil {
+		return []ModelEmitterResult{}, err
+	}
+
+	ref := func(metaType types.MetaType) string {
+		return jsonSchemaRef(model.Package, metaType)
+	}
+
+	schema := self.header(model.Package, model.Name)
+	schema = append(schema, self.objectSchema(model, ref, false)...)
+
+	result, err := jsonResult(jsonSchemaPath(model.Package, model.Name), schema)
+	return []ModelEmitterResult{result}, err
+}
+
+// objectSchema returns the schema of model, referencing models and enums
+// with ref. ZeroOrOne fields also accept null when nullable is set.
+func (self JSONSchemaEmitter) objectSchema(model types.MetaModel, ref func(types.MetaType) string, nullable bool) jsonObject {
+	properties := jsonObject{}
+	required := []string{}
+	for _, field := range model.Fields {
+		schema := self.fieldSchema(field, ref)
+		if nullable && field.Cardinality == types.ZeroOrOne {
+			schema = nullableSchema(schema)
+		}
+
+		properties = append(properties, jsonMember{field.Name, schema})
+		if field.Cardinality == types.One {
+			required = append(required, field.Name)
+		}
+	}
+
+	schema := jsonObject{
+		{"type", "object"},
+		{"properties", properties},
+	}
+	if len(required) > 0 {
+		schema = append(schema, jsonMember{"required", required})
+	}
+
+	return append(schema, jsonMember{"additionalProperties", false})
+}
+
+// enumSchema returns the schema of enum
+func enumSchema(enum types.MetaEnum) jsonObject {
+	return jsonObject{
+		{"type", "string"},
+		{"enum", enum.Literals},
+	}
+}
+
+// nullableSchema returns schema accepting null as well
+func nullableSchema(schema jsonObject) jsonObject {
+	if len(schema) > 0 && schema[0].Key == "type" {
+		return append(jsonObject{{"type", []any{schema[0].Value, "null"}}}, schema[1:]...)
+	}
+
+	return jsonObject{{"anyOf", []any{schema, jsonObject{{"type", "null"}}}}}
+}
+
+func (self JSONSchemaEmitter) header(pkg, name string) jsonObject {
+	schema := jsonObject{{"$schema", JSON_SCHEMA_DIALECT}}
+	if self.BaseURI != "" {
+		schema = append(schema, jsonMember{"$id", self.BaseURI + "/" + jsonSchemaPath(pkg, name)})
+	}
+
+	return append(schema, jsonMember{"title", name})
+}
+
+// fieldSchema returns the schema of field, referencing models and enums with ref
+func (self JSONSchemaEmitter) fieldSchema(field types.MetaModelField, ref func(types.MetaType) string) jsonObject {
+	schema := jsonObject{}
+	if mapping, found := self.resolver.mapPrimitive(field.Type, types.TargetJSONSchema); found {
+		schema = append(schema, jsonMember{"type", mapping.Type})
+		if mapping.Format != "" {
+			schema = append(schema, jsonMember{"format", mapping.Format})
+		}
+	} else {
+		schema = append(schema, jsonMember{"$ref", ref(field.Type)})
+	}
+
+	if field.Cardinality == types.Collection {
+		return jsonObject{{"type", "array"}, {"items", schema}}
+	}
+
+	return schema
+}
+
+func jsonSchemaPath(pkg, name string) string {
+	return path.Join(pkg, name+JSON_SCHEMA_EXTENSION)
+}
+
+// jsonSchemaRef returns the path of the schema of metaType relative to
+// the schemas of pkg
+func jsonSchemaRef(pkg string, metaType types.MetaType) string {
+	if metaType.Package == "" || metaType.Package == pkg {
+		return metaType.Name + JSON_SCHEMA_EXTENSION
+	}
+
+	return path.Join("..", jsonSchemaPath(metaType.Package, metaType.Name))
+}
+
+func jsonResult(path string, value any) (ModelEmitterResult, error) {
+	content, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return ModelEmitterResult{}, err
+	}
+
+	return ModelEmitterResult{Path: path, Content: string(content) + "\n"}, nil
+}
+
+// jsonObject is a JSON object keeping its members in order
+type jsonObject []jsonMember
+
+type jsonMember struct {
+	Key   string
+	Value any
+}
+
+func (self jsonObject) MarshalJSON() ([]byte, error) {
+	buffer := bytes.Buffer{}
+	buffer.WriteByte('{')
+	for i, member := range self {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+
+		key, err := json.Marshal(member.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(member.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		buffer.Write(key)
+		buffer.WriteByte(':')
+		buffer.Write(value)
+	}
+	buffer.WriteByte('}')
+
+	return buffer.Bytes(), nil
+}
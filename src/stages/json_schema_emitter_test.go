@@ -0,0 +1,133 @@
+package stages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONSchemaEmitter(t *testing.T) {
+	file := parseGinco(t, `package roleplaying {
+	enum Clan {
+		literals {
+			Brujah
+			Ventrue
+		}
+	}
+
+	model Item {
+		fields {
+			=? name string
+		}
+	}
+
+	model Character {
+		fields {
+			=1 id uuid
+			=? nickname string
+			=1 clan Clan
+			=* tags string
+			-* loot Item
+		}
+	}
+}
+
+package horror {
+	model Vampire {
+		fields {
+			-1 sire roleplaying.Character
+		}
+	}
+}`)
+
+	results, err := JSONSchemaEmitter{}.GenerateFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"roleplaying/Item.schema.json",
+		"roleplaying/Character.schema.json",
+		"horror/Vampire.schema.json",
+		"roleplaying/Clan.schema.json",
+	}, resultPaths(results))
+
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "Item",
+		"type": "object",
+		"properties": {
+			"name": { "type": "string" }
+		},
+		"additionalProperties": false
+	}`, results[0].Content)
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "Character",
+		"type": "object",
+		"properties": {
+			"id": { "type": "string", "format": "uuid" },
+			"nickname": { "type": "string" },
+			"clan": { "$ref": "Clan.schema.json" },
+			"tags": { "type": "array", "items": { "type": "string" } },
+			"loot": { "type": "array", "items": { "$ref": "Item.schema.json" } }
+		},
+		"required": ["id", "clan"],
+		"additionalProperties": false
+	}`, results[1].Content)
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "Vampire",
+		"type": "object",
+		"properties": {
+			"sire": { "$ref": "../roleplaying/Character.schema.json" }
+		},
+		"required": ["sire"],
+		"additionalProperties": false
+	}`, results[2].Content)
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "Clan",
+		"type": "string",
+		"enum": ["Brujah", "Ventrue"]
+	}`, results[3].Content)
+}
+
+func TestJSONSchemaEmitterBaseURI(t *testing.T) {
+	file := parseGinco(t, CLAN_GINCO)
+
+	results, err := JSONSchemaEmitter{BaseURI: "https://example.com/schemas"}.GenerateFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "$id": "https://example.com/schemas/roleplaying/Clan.schema.json",
+  "title": "Clan",
+  "type": "string",
+  "enum": [
+    "Brujah",
+    "Ventrue"
+  ]
+}
+`, results[0].Content)
+}
+
+func TestJSONSchemaEmitterRequiresFile(t *testing.T) {
+	file := parseGinco(t, `package people {
+	scalar Email string
+
+	model Person {
+		fields {
+			=1 email Email
+		}
+	}
+}`)
+	model := file.Packages[0].Models[0]
+
+	_, err := JSONSchemaEmitter{}.Generate(model)
+	assertErrorContains(t, err, []string{"types of the model are unknown", "NewJSONSchemaEmitter"})
+
+	emitter, err := NewJSONSchemaEmitter(file)
+	assert.NoError(t, err)
+	results, err := emitter.Generate(model)
+	assert.NoError(t, err)
+	assert.Contains(t, results[0].Content, `"email": {
+      "type": "string"
+    }`)
+}
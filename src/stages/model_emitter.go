@@ -1,6 +1,11 @@
 package stages
 
-import "github.com/trudso/ginco/types"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/trudso/ginco/types"
+)
 
 type ModelEmitterResult struct {
 	Path    string
@@ -11,6 +16,118 @@ type ModelEmitter interface {
 	Generate(model types.MetaModel) ([]ModelEmitterResult, error)
 }
 
+// MetaFileEmitter generates output for a whole MetaFile, for emitters
+// which need to see other models or write one output per package
+type MetaFileEmitter interface {
+	GenerateFile(file types.MetaFile) ([]ModelEmitterResult, error)
+}
+
+// NamedEmitter can be implemented by emitters to name themselves in errors
+type NamedEmitter interface {
+	Name() string
+}
+
+// EmitModel runs every emitter for the model and returns all results.
+// When two results share a path only the first one is kept.
 func EmitModel(model types.MetaModel, emitters []ModelEmitter) ([]ModelEmitterResult, error) {
-	return []ModelEmitterResult{}, nil
+	results := []ModelEmitterResult{}
+	emittedBy := map[string]string{}
+	errs := []error{}
+	modelName := qualifiedName(model.Package, model.Name)
+
+	for _, emitter := range emitters {
+		name := emitterName(emitter)
+		emitted, err := emitter.Generate(model)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("emitter %s failed for model %s: %w", name, modelName, err))
+			continue
+		}
+
+		for _, result := range emitted {
+			if other, found := emittedBy[result.Path]; found {
+				errs = append(errs, fmt.Errorf("emitters %s and %s both write %s for model %s", other, name, result.Path, modelName))
+				continue
+			}
+
+			emittedBy[result.Path] = name
+			results = append(results, result)
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// EmitModels runs every emitter for every model of file
+func EmitModels(file types.MetaFile, emitters []ModelEmitter) ([]ModelEmitterResult, error) {
+	results := []ModelEmitterResult{}
+	errs := []error{}
+	for _, pkg := range file.Packages {
+		for _, model := range pkg.Models {
+			emitted, err := EmitModel(model, emitters)
+			if err != nil {
+				errs = append(errs, err)
+			}
+
+			results = append(results, emitted...)
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// EmitMetaFile runs every emitter for the file and returns all results.
+// When two results share a path only the first one is kept.
+func EmitMetaFile(file types.MetaFile, emitters []MetaFileEmitter) ([]ModelEmitterResult, error) {
+	results := []ModelEmitterResult{}
+	emittedBy := map[string]string{}
+	errs := []error{}
+
+	for _, emitter := range emitters {
+		name := emitterName(emitter)
+		emitted, err := emitter.GenerateFile(file)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("emitter %s failed: %w", name, err))
+			continue
+		}
+
+		for _, result := range emitted {
+			if other, found := emittedBy[result.Path]; found {
+				errs = append(errs, fmt.Errorf("emitters %s and %s both write %s", other, name, result.Path))
+				continue
+			}
+
+			emittedBy[result.Path] = name
+			results = append(results, result)
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// generateModels runs emitter for every model of file, for file emitters
+// generating one output per model
+func generateModels(file types.MetaFile, emitter ModelEmitter) ([]ModelEmitterResult, error) {
+	results := []ModelEmitterResult{}
+	errs := []error{}
+	for _, pkg := range file.Packages {
+		for _, model := range pkg.Models {
+			emitted, err := emitter.Generate(model)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("model %s: %w", qualifiedName(model.Package, model.Name), err))
+				continue
+			}
+
+			results = append(results, emitted...)
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
+
+func emitterName(emitter any) string {
+	if named, ok := emitter.(NamedEmitter); ok {
+		return named.Name()
+	}
+
+	return fmt.Sprintf("%T", emitter)
 }
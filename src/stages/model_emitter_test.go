@@ -0,0 +1,65 @@
+package stages
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trudso/ginco/types"
+)
+
+// pathEmitter writes one empty file per path for every model
+type pathEmitter struct {
+	paths []string
+}
+
+func (self pathEmitter) Generate(model types.MetaModel) ([]ModelEmitterResult, error) {
+	results := []ModelEmitterResult{}
+	for _, path := range self.paths {
+		results = append(results, ModelEmitterResult{Path: model.Name + "/" + path, Content: model.Name})
+	}
+
+	return results, nil
+}
+
+type namedFailingEmitter struct{}
+
+func (self namedFailingEmitter) Name() string {
+	return "failing"
+}
+
+func (self namedFailingEmitter) Generate(model types.MetaModel) ([]ModelEmitterResult, error) {
+	return nil, errors.New("boom")
+}
+
+func TestEmitModel(t *testing.T) {
+	testCases := []struct {
+		emitters            []ModelEmitter
+		expectedPaths       []string
+		expectedErrorValues []string
+	}{
+		{nil, []string{}, nil},
+		{[]ModelEmitter{pathEmitter{[]string{"a.go", "b.go"}}, pathEmitter{[]string{"c.sql"}}}, []string{"Character/a.go", "Character/b.go", "Character/c.sql"}, nil},
+		{
+			[]ModelEmitter{pathEmitter{[]string{"a.go"}}, namedFailingEmitter{}, pathEmitter{[]string{"b.go"}}},
+			[]string{"Character/a.go", "Character/b.go"},
+			[]string{"emitter failing failed for model roleplaying.Character: boom"},
+		},
+		{
+			[]ModelEmitter{pathEmitter{[]string{"a.go"}}, pathEmitter{[]string{"b.go", "a.go"}}},
+			[]string{"Character/a.go", "Character/b.go"},
+			[]string{"emitters stages.pathEmitter and stages.pathEmitter both write Character/a.go for model roleplaying.Character"},
+		},
+	}
+
+	for _, tc := range testCases {
+		results, err := EmitModel(types.MetaModel{Package: "roleplaying", Name: "Character"}, tc.emitters)
+		assertErrorContains(t, err, tc.expectedErrorValues)
+
+		paths := []string{}
+		for _, result := range results {
+			paths = append(paths, result.Path)
+		}
+		assert.Equal(t, tc.expectedPaths, paths)
+	}
+}
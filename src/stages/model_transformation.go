@@ -1,11 +1,104 @@
 package stages
 
-import "github.com/trudso/ginco/types"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/trudso/ginco/types"
+)
 
 type ModelTransformer interface {
 	Transform(model types.MetaModel) ([]types.MetaModel, error)
 }
 
+// MetaFileTransformer transforms a whole MetaFile, for transformations
+// which need to see other models than the one being transformed
+type MetaFileTransformer interface {
+	TransformFile(file types.MetaFile) (types.MetaFile, error)
+}
+
+// transformErrors collects the errors of transformers. Diagnostics are
+// kept as they are, they already point at the offending source.
+type transformErrors struct {
+	diagnostics types.Diagnostics
+	errs        []error
+}
+
+// add collects err, other errors than diagnostics are prefixed with context
+func (self *transformErrors) add(err error, context string) {
+	switch err.(type) {
+	case nil:
+	case types.Diagnostic, types.Diagnostics:
+		self.diagnostics = collectError(self.diagnostics, err)
+	default:
+		if context != "" {
+			err = fmt.Errorf("%s: %w", context, err)
+		}
+		self.errs = append(self.errs, err)
+	}
+}
+
+// Err returns the collected diagnostics as types.Diagnostics, unless
+// there are other errors as well
+func (self transformErrors) Err() error {
+	if len(self.errs) == 0 {
+		return self.diagnostics.Err()
+	}
+
+	return errors.Join(append([]error{self.diagnostics.Err()}, self.errs...)...)
+}
+
+// TransformModel runs every transformer in order, feeding the models
+// returned by one transformer into the next
 func TransformModel(model types.MetaModel, transformers []ModelTransformer) ([]types.MetaModel, error) {
-	return []types.MetaModel{model}, nil
+	models := []types.MetaModel{model}
+	errs := transformErrors{}
+	for _, transformer := range transformers {
+		transformed := []types.MetaModel{}
+		for _, current := range models {
+			results, err := transformer.Transform(current)
+			errs.add(err, "model "+qualifiedName(current.Package, current.Name))
+
+			transformed = append(transformed, results...)
+		}
+
+		models = transformed
+	}
+
+	return models, errs.Err()
+}
+
+// TransformModels runs the transformers on every model of file. The
+// models a model is transformed into replace it in its package.
+func TransformModels(file types.MetaFile, transformers []ModelTransformer) (types.MetaFile, error) {
+	transformed := file
+	transformed.Packages = make([]types.MetaPackage, len(file.Packages))
+	errs := transformErrors{}
+	for i, pkg := range file.Packages {
+		models := []types.MetaModel{}
+		for _, model := range pkg.Models {
+			results, err := TransformModel(model, transformers)
+			errs.add(err, "")
+
+			models = append(models, results...)
+		}
+
+		pkg.Models = models
+		transformed.Packages[i] = pkg
+	}
+
+	return transformed, errs.Err()
+}
+
+// TransformMetaFile runs every transformer in order, feeding the file
+// returned by one transformer into the next
+func TransformMetaFile(file types.MetaFile, transformers []MetaFileTransformer) (types.MetaFile, error) {
+	errs := transformErrors{}
+	for _, transformer := range transformers {
+		transformed, err := transformer.TransformFile(file)
+		errs.add(err, "")
+		file = transformed
+	}
+
+	return file, errs.Err()
 }
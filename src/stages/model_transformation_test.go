@@ -0,0 +1,122 @@
+package stages
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trudso/ginco/types"
+)
+
+// suffixTransformer appends a suffix to the name of every model
+type suffixTransformer struct {
+	suffix string
+}
+
+func (self suffixTransformer) Transform(model types.MetaModel) ([]types.MetaModel, error) {
+	model.Name += self.suffix
+	return []types.MetaModel{model}, nil
+}
+
+// splitTransformer turns every model into an input and an output model
+type splitTransformer struct{}
+
+func (self splitTransformer) Transform(model types.MetaModel) ([]types.MetaModel, error) {
+	input, output := model, model
+	input.Name += "Input"
+	output.Name += "Output"
+	return []types.MetaModel{input, output}, nil
+}
+
+// failingTransformer fails for the model called name
+type failingTransformer struct {
+	name string
+}
+
+func (self failingTransformer) Transform(model types.MetaModel) ([]types.MetaModel, error) {
+	if model.Name == self.name {
+		return []types.MetaModel{model}, errors.New("failed")
+	}
+
+	return []types.MetaModel{model}, nil
+}
+
+func modelNames(models []types.MetaModel) []string {
+	names := []string{}
+	for _, model := range models {
+		names = append(names, model.Name)
+	}
+
+	return names
+}
+
+func TestTransformModel(t *testing.T) {
+	testCases := []struct {
+		transformers        []ModelTransformer
+		expectedModelNames  []string
+		expectedErrorValues []string
+	}{
+		{nil, []string{"Character"}, nil},
+		{[]ModelTransformer{suffixTransformer{"A"}, suffixTransformer{"B"}}, []string{"CharacterAB"}, nil},
+		{[]ModelTransformer{splitTransformer{}, suffixTransformer{"Dto"}}, []string{"CharacterInputDto", "CharacterOutputDto"}, nil},
+		{
+			[]ModelTransformer{splitTransformer{}, failingTransformer{"CharacterInput"}, failingTransformer{"CharacterOutput"}},
+			[]string{"CharacterInput", "CharacterOutput"},
+			[]string{"model roleplaying.CharacterInput: failed", "model roleplaying.CharacterOutput: failed"},
+		},
+	}
+
+	for _, tc := range testCases {
+		models, err := TransformModel(types.MetaModel{Package: "roleplaying", Name: "Character"}, tc.transformers)
+		assertErrorContains(t, err, tc.expectedErrorValues)
+		assert.Equal(t, tc.expectedModelNames, modelNames(models))
+	}
+}
+
+func TestTransformModels(t *testing.T) {
+	file := types.MetaFile{
+		Packages: []types.MetaPackage{
+			{Name: "a", Models: []types.MetaModel{{Package: "a", Name: "A"}, {Package: "a", Name: "B"}}},
+			{Name: "b", Models: []types.MetaModel{{Package: "b", Name: "C"}}},
+		},
+	}
+
+	transformed, err := TransformModels(file, []ModelTransformer{splitTransformer{}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"AInput", "AOutput", "BInput", "BOutput"}, modelNames(transformed.Packages[0].Models))
+	assert.Equal(t, []string{"CInput", "COutput"}, modelNames(transformed.Packages[1].Models))
+	assert.Equal(t, []string{"A", "B"}, modelNames(file.Packages[0].Models))
+}
+
+func TestTransformMetaFile(t *testing.T) {
+	file, _, err := parseMetaFile(`package roleplaying {
+	model Character {
+		fields {
+			=1 id uuid
+		}
+	}
+
+	@inherits(Character)
+	model Player {
+		fields {
+			=1 handle string
+		}
+	}
+
+	@inherits(Missing)
+	model Boss {}
+}`, 0)
+	assert.NoError(t, err)
+
+	transformed, err := TransformMetaFile(file, []MetaFileTransformer{InheritanceTransformer{}})
+	assertErrorContains(t, err, []string{"15:12: Unresolved base model roleplaying.Missing"})
+
+	diagnostics, ok := err.(types.Diagnostics)
+	assert.True(t, ok)
+	assert.Equal(t, 1, len(diagnostics))
+	assert.Equal(t, types.CodeUnresolvedType, diagnostics[0].Code)
+
+	models := transformed.Packages[0].Models
+	assert.Equal(t, []string{"Character", "Player", "Boss"}, modelNames(models))
+	assert.Equal(t, 2, len(models[1].Fields))
+}
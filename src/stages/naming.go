@@ -0,0 +1,148 @@
+package stages
+
+import (
+	"strings"
+	"unicode"
+)
+
+// initialisms are written in upper case in Go identifiers
+var initialisms = map[string]bool{
+	"api":  true,
+	"html": true,
+	"http": true,
+	"id":   true,
+	"json": true,
+	"sql":  true,
+	"uri":  true,
+	"url":  true,
+	"uuid": true,
+	"xml":  true,
+}
+
+// splitWords splits camelCase, PascalCase, snake_case and kebab-case
+// names into lower case words
+func splitWords(name string) []string {
+	words := []string{}
+	word := []rune{}
+	runes := []rune(name)
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = []rune{}
+		}
+	}
+
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+			continue
+		case unicode.IsUpper(r) && i > 0:
+			previous := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextIsLower) {
+				flush()
+			}
+		}
+		word = append(word, r)
+	}
+	flush()
+
+	return words
+}
+
+func capitalize(word string) string {
+	runes := []rune(word)
+	if len(runes) == 0 {
+		return word
+	}
+
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// pascalCase converts name to PascalCase, e.g. "first_name" -> "FirstName"
+func pascalCase(name string) string {
+	builder := strings.Builder{}
+	for _, word := range splitWords(name) {
+		builder.WriteString(capitalize(word))
+	}
+
+	return builder.String()
+}
+
+// camelCase converts name to camelCase, e.g. "first_name" -> "firstName"
+func camelCase(name string) string {
+	words := splitWords(name)
+	if len(words) == 0 {
+		return ""
+	}
+
+	return words[0] + pascalCase(strings.Join(words[1:], "_"))
+}
+
+// snakeCase converts name to snake_case, e.g. "FirstName" -> "first_name"
+func snakeCase(name string) string {
+	return strings.Join(splitWords(name), "_")
+}
+
+// goName converts name to an exported Go identifier, e.g. "user_id" -> "UserID"
+func goName(name string) string {
+	builder := strings.Builder{}
+	for _, word := range splitWords(name) {
+		if initialisms[word] {
+			builder.WriteString(strings.ToUpper(word))
+		} else {
+			builder.WriteString(capitalize(word))
+		}
+	}
+
+	return builder.String()
+}
+
+// goPackageName converts name to a Go package name, e.g. "role-playing" -> "roleplaying"
+func goPackageName(name string) string {
+	builder := strings.Builder{}
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			builder.WriteRune(r)
+		}
+	}
+
+	pkg := builder.String()
+	if pkg == "" || unicode.IsDigit([]rune(pkg)[0]) {
+		pkg = "pkg" + pkg
+	}
+
+	return pkg
+}
+
+// pluralize returns the english plural of a singular name, e.g. "Category" -> "Categories"
+func pluralize(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return name[:len(name)-1] + "ies"
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return name + "es"
+	default:
+		return name + "s"
+	}
+}
+
+// singularize returns the english singular of a plural name, e.g. "Categories" -> "Category"
+func singularize(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, "ies"):
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(lower, "ses"), strings.HasSuffix(lower, "xes"), strings.HasSuffix(lower, "zes"),
+		strings.HasSuffix(lower, "ches"), strings.HasSuffix(lower, "shes"):
+		return name[:len(name)-2]
+	case strings.HasSuffix(lower, "s") && !strings.HasSuffix(lower, "ss"):
+		return name[:len(name)-1]
+	default:
+		return name
+	}
+}
@@ -0,0 +1,58 @@
+package stages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNaming(t *testing.T) {
+	tests := []struct {
+		name   string
+		pascal string
+		camel  string
+		snake  string
+		goName string
+	}{
+		{"id", "Id", "id", "id", "ID"},
+		{"first_name", "FirstName", "firstName", "first_name", "FirstName"},
+		{"firstName", "FirstName", "firstName", "first_name", "FirstName"},
+		{"HTTPServer", "HttpServer", "httpServer", "http_server", "HTTPServer"},
+		{"user-id", "UserId", "userId", "user_id", "UserID"},
+		{"Character2Item", "Character2Item", "character2Item", "character2_item", "Character2Item"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.pascal, pascalCase(test.name))
+			assert.Equal(t, test.camel, camelCase(test.name))
+			assert.Equal(t, test.snake, snakeCase(test.name))
+			assert.Equal(t, test.goName, goName(test.name))
+		})
+	}
+}
+
+func TestGoPackageName(t *testing.T) {
+	assert.Equal(t, "roleplaying", goPackageName("RolePlaying"))
+	assert.Equal(t, "roleplaying", goPackageName("role-playing"))
+	assert.Equal(t, "pkg2d", goPackageName("2d"))
+}
+
+func TestInflection(t *testing.T) {
+	tests := []struct {
+		singular string
+		plural   string
+	}{
+		{"character", "characters"},
+		{"Category", "Categories"},
+		{"day", "days"},
+		{"class", "classes"},
+		{"box", "boxes"},
+		{"match", "matches"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.plural, pluralize(test.singular))
+		assert.Equal(t, test.singular, singularize(test.plural))
+	}
+}
@@ -0,0 +1,139 @@
+package stages
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/trudso/ginco/types"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	OPENAPI_VERSION     = "3.1.0"
+	OPENAPI_SCHEMAS_REF = "#/components/schemas/"
+)
+
+/*
+	package roleplaying {
+		model Character {
+			fields {
+				=1 id uuid
+				=? weapon Item
+			}
+		}
+	}
+
+generates openapi.yaml
+
+	openapi: 3.1.0
+	info:
+	  title: ginco
+	  version: 0.0.0
+	components:
+	  schemas:
+	    roleplaying.Character:
+	      type: object
+	      properties:
+	        id:
+	          type: string
+	          format: uuid
+	        weapon:
+	          anyOf:
+	            - $ref: '#/components/schemas/roleplaying.Item'
+	            - type: "null"
+	      required:
+	        - id
+	      additionalProperties: false
+*/
+
+// OpenAPIEmitter generates an OpenAPI 3.1 document holding a schema per
+// model and enum in components/schemas, named by their qualified name so
+// models of different packages never clash. ZeroOrOne fields are optional
+// and nullable, fields with cardinality One are required and collections
+// are arrays.
+type OpenAPIEmitter struct {
+	// Title and Version fill the info object of the document, which
+	// default to "ginco" and "0.0.0"
+	Title   string
+	Version string
+	// JSON writes openapi.json instead of openapi.yaml
+	JSON bool
+}
+
+func (self OpenAPIEmitter) Name() string {
+	return "openapi"
+}
+
+func (self OpenAPIEmitter) GenerateFile(file types.MetaFile) ([]ModelEmitterResult, error) {
+	resolver, err := newTypeResolver(file)
+	if err != nil {
+		return []ModelEmitterResult{}, err
+	}
+	schemaEmitter := JSONSchemaEmitter{resolver: resolver}
+
+	ref := func(metaType types.MetaType) string {
+		return OPENAPI_SCHEMAS_REF + formatMetaType(metaType)
+	}
+
+	schemas := jsonObject{}
+	for _, pkg := range file.Packages {
+		for _, enum := range pkg.Enums {
+			schemas = append(schemas, jsonMember{qualifiedName(pkg.Name, enum.Name), enumSchema(enum)})
+		}
+
+		for _, model := range pkg.Models {
+			schemas = append(schemas, jsonMember{qualifiedName(pkg.Name, model.Name), schemaEmitter.objectSchema(model, ref, true)})
+		}
+	}
+
+	document := jsonObject{
+		{"openapi", OPENAPI_VERSION},
+		{"info", jsonObject{
+			{"title", valueOr(self.Title, "ginco")},
+			{"version", valueOr(self.Version, "0.0.0")},
+		}},
+		{"components", jsonObject{{"schemas", schemas}}},
+	}
+
+	if self.JSON {
+		result, err := jsonResult("openapi.json", document)
+		return []ModelEmitterResult{result}, err
+	}
+
+	content := bytes.Buffer{}
+	encoder := yaml.NewEncoder(&content)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(document); err != nil {
+		return []ModelEmitterResult{}, fmt.Errorf("marshalling openapi.yaml: %w", err)
+	}
+
+	return []ModelEmitterResult{{Path: "openapi.yaml", Content: content.String()}}, nil
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+
+	return value
+}
+
+// MarshalYAML keeps the members in order when marshalling to YAML
+func (self jsonObject) MarshalYAML() (any, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, member := range self {
+		key := &yaml.Node{}
+		if err := key.Encode(member.Key); err != nil {
+			return nil, err
+		}
+
+		value := &yaml.Node{}
+		if err := value.Encode(member.Value); err != nil {
+			return nil, err
+		}
+
+		node.Content = append(node.Content, key, value)
+	}
+
+	return node, nil
+}
@@ -0,0 +1,116 @@
+package stages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const OPENAPI_GINCO = `package roleplaying {
+	enum Clan {
+		literals {
+			Brujah
+		}
+	}
+
+	model Character {
+		fields {
+			=1 id uuid
+			=? nickname string
+			=? clan Clan
+			=* tags string
+		}
+	}
+}
+
+package horror {
+	model Vampire {
+		fields {
+			-1 sire roleplaying.Character
+		}
+	}
+}`
+
+func TestOpenAPIEmitter(t *testing.T) {
+	file := parseGinco(t, OPENAPI_GINCO)
+
+	results, err := OpenAPIEmitter{Title: "Roleplaying", Version: "1.2.0"}.GenerateFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"openapi.yaml"}, resultPaths(results))
+	assert.Equal(t, `openapi: 3.1.0
+info:
+  title: Roleplaying
+  version: 1.2.0
+components:
+  schemas:
+    roleplaying.Clan:
+      type: string
+      enum:
+        - Brujah
+    roleplaying.Character:
+      type: object
+      properties:
+        id:
+          type: string
+          format: uuid
+        nickname:
+          type:
+            - string
+            - "null"
+        clan:
+          anyOf:
+            - $ref: '#/components/schemas/roleplaying.Clan'
+            - type: "null"
+        tags:
+          type: array
+          items:
+            type: string
+      required:
+        - id
+      additionalProperties: false
+    horror.Vampire:
+      type: object
+      properties:
+        sire:
+          $ref: '#/components/schemas/roleplaying.Character'
+      required:
+        - sire
+      additionalProperties: false
+`, results[0].Content)
+}
+
+func TestOpenAPIEmitterJSON(t *testing.T) {
+	file := parseGinco(t, OPENAPI_GINCO)
+
+	results, err := OpenAPIEmitter{JSON: true}.GenerateFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"openapi.json"}, resultPaths(results))
+	assert.JSONEq(t, `{
+		"openapi": "3.1.0",
+		"info": { "title": "ginco", "version": "0.0.0" },
+		"components": {
+			"schemas": {
+				"roleplaying.Clan": { "type": "string", "enum": ["Brujah"] },
+				"roleplaying.Character": {
+					"type": "object",
+					"properties": {
+						"id": { "type": "string", "format": "uuid" },
+						"nickname": { "type": ["string", "null"] },
+						"clan": { "anyOf": [{ "$ref": "#/components/schemas/roleplaying.Clan" }, { "type": "null" }] },
+						"tags": { "type": "array", "items": { "type": "string" } }
+					},
+					"required": ["id"],
+					"additionalProperties": false
+				},
+				"horror.Vampire": {
+					"type": "object",
+					"properties": {
+						"sire": { "$ref": "#/components/schemas/roleplaying.Character" }
+					},
+					"required": ["sire"],
+					"additionalProperties": false
+				}
+			}
+		}
+	}`, results[0].Content)
+}
@@ -5,6 +5,8 @@ import (
 	"slices"
 	"strings"
 	"unicode"
+
+	"github.com/trudso/ginco/types"
 )
 
 type TokenType int
@@ -16,6 +18,8 @@ const (
 	TT_IDENTIFIER
 	TT_SYMBOL
 	TT_RUNE
+	TT_PATH
+	TT_STRING
 	TT_INVALID
 	TT_EOF
 )
@@ -175,6 +179,112 @@ func popNumber(content string, startIdx int) (Token, int, error) {
 	}, endIdx, nil
 }
 
+// popPath returns the next run of characters
+// up to the first whitespace
+func popPath(content string, startIdx int) (Token, int, error) {
+	realStartIdx := firstValidTokenIndex(content, startIdx)
+	if realStartIdx == -1 {
+		return Token{}, startIdx, formatParsingError("No path found", content, startIdx)
+	}
+
+	endIdx := realStartIdx
+	for i := realStartIdx; i < len(content); i++ {
+		if slices.Contains(skippables, string(content[i])) {
+			break
+		}
+
+		endIdx = i + 1
+	}
+
+	return Token{
+		Type:     TT_PATH,
+		Position: realStartIdx,
+		Value:    content[realStartIdx:endIdx],
+	}, endIdx, nil
+}
+
+// popDecimal returns a number with an optional sign and fraction, e.g. -12.5
+func popDecimal(content string, startIdx int) (Token, int, error) {
+	realStartIdx := firstValidTokenIndex(content, startIdx)
+	if realStartIdx == -1 {
+		return Token{}, startIdx, formatParsingError("No number found", content, startIdx)
+	}
+
+	endIdx := realStartIdx
+	if content[endIdx] == '-' {
+		endIdx++
+	}
+
+	digitsStartIdx := endIdx
+	for endIdx < len(content) && unicode.IsDigit(rune(content[endIdx])) {
+		endIdx++
+	}
+
+	if endIdx == digitsStartIdx {
+		return Token{}, startIdx, formatParsingError("No number found", content, startIdx)
+	}
+
+	if endIdx+1 < len(content) && content[endIdx] == '.' && unicode.IsDigit(rune(content[endIdx+1])) {
+		endIdx++
+		for endIdx < len(content) && unicode.IsDigit(rune(content[endIdx])) {
+			endIdx++
+		}
+	}
+
+	return Token{
+		Type:     TT_NUMBER,
+		Position: realStartIdx,
+		Value:    content[realStartIdx:endIdx],
+	}, endIdx, nil
+}
+
+// popString returns the content of a double quoted string,
+// supporting \" and \\ escapes
+func popString(content string, startIdx int) (Token, int, error) {
+	realStartIdx := firstValidTokenIndex(content, startIdx)
+	if realStartIdx == -1 || content[realStartIdx] != '"' {
+		return Token{}, startIdx, formatParsingError("No string found", content, startIdx)
+	}
+
+	var value strings.Builder
+	for i := realStartIdx + 1; i < len(content); i++ {
+		switch content[i] {
+		case '"':
+			return Token{
+				Type:     TT_STRING,
+				Position: realStartIdx,
+				Value:    value.String(),
+			}, i + 1, nil
+		case '\\':
+			if i+1 < len(content) && (content[i+1] == '"' || content[i+1] == '\\') {
+				i++
+			}
+		case '\n':
+			return Token{}, realStartIdx, formatParsingError("Unterminated string", content, realStartIdx)
+		}
+
+		value.WriteByte(content[i])
+	}
+
+	return Token{}, realStartIdx, formatParsingError("Unterminated string", content, realStartIdx)
+}
+
+// popBody pops the scope holding the body of declaration, which must
+// directly follow startIdx
+func popBody(content string, startIdx int, declaration string) (Token, int, error) {
+	scopeIdx := firstValidTokenIndex(content, startIdx)
+	if scopeIdx == -1 || content[scopeIdx] != '{' {
+		return Token{}, startIdx, formatParsingError(fmt.Sprintf("Expected { after %s", declaration), content, max(scopeIdx, startIdx))
+	}
+
+	scope, nextIdx, err := popScope(content, scopeIdx)
+	if err != nil {
+		return scope, startIdx, err
+	}
+
+	return scope, nextIdx, nil
+}
+
 func popScope(content string, startIdx int) (Token, int, error) {
 	realStartIdx := firstValidTokenIndex(content, startIdx)
 	if realStartIdx == -1 {
@@ -274,14 +384,140 @@ func getSubString(content string, startIdx, endIdx int) string {
 	return content[startIdx:endIdx]
 }
 
-func formatParsingError(errorMessage, content string, atIdx int) error {
+func formatParsingError(errorMessage, content string, atIdx int) types.Diagnostic {
 	// trim idx
 	idx := max(0, min(max(atIdx, 0), len(content)-1))
 
-	contentToIdx := content[:idx]
-	line := strings.Count(contentToIdx, "\n") + 1
-	lineStartIdx := strings.LastIndex(contentToIdx, "\n")
+	diagnostic := types.Diagnostic{
+		Severity: types.SeverityError,
+		Code:     types.CodeSyntax,
+		Message:  errorMessage,
+		Position: types.Position{Offset: idx},
+		Length:   tokenLength(content, idx),
+	}
+	locate(&diagnostic.Position, content)
+	return diagnostic
+}
+
+// tokenLength returns the number of bytes of the token starting at idx,
+// a whole word for identifiers and numbers and a single rune otherwise
+func tokenLength(content string, idx int) int {
+	if idx >= len(content) || unicode.IsSpace(rune(content[idx])) {
+		return 0
+	}
+
+	if !isWordRune(rune(content[idx])) {
+		return 1
+	}
+
+	end := idx
+	for end < len(content) && isWordRune(rune(content[end])) {
+		end++
+	}
+
+	return end - idx
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// relocateError moves a parsing error found inside a nested scope,
+// starting at scopeStart in content, to its position in content
+func relocateError(err error, scopeStart int, content string) error {
+	switch diagnostics := err.(type) {
+	case types.Diagnostic:
+		diagnostics.Position.Offset += scopeStart
+		locate(&diagnostics.Position, content)
+		return diagnostics
+	case types.Diagnostics:
+		relocated := make(types.Diagnostics, len(diagnostics))
+		for i, diagnostic := range diagnostics {
+			diagnostic.Position.Offset += scopeStart
+			locate(&diagnostic.Position, content)
+			relocated[i] = diagnostic
+		}
+		return relocated
+	}
+
+	return err
+}
+
+// Parse functions recovering from errors return their partial result
+// along with every error found as types.Diagnostics. Any other error
+// means nothing could be parsed.
+func isRecovered(err error) bool {
+	_, ok := err.(types.Diagnostics)
+	return ok
+}
+
+// collectError appends the diagnostics err consists of
+func collectError(diagnostics types.Diagnostics, err error) types.Diagnostics {
+	switch e := err.(type) {
+	case nil:
+		return diagnostics
+	case types.Diagnostic:
+		return append(diagnostics, e)
+	case types.Diagnostics:
+		return append(diagnostics, e...)
+	}
+
+	return append(diagnostics, types.Diagnostic{
+		Severity: types.SeverityError,
+		Code:     types.CodeSyntax,
+		Message:  err.Error(),
+	})
+}
+
+// errorOffset returns the offset of the last problem in err
+func errorOffset(err error, fallback int) int {
+	switch e := err.(type) {
+	case types.Diagnostic:
+		return e.Position.Offset
+	case types.Diagnostics:
+		if len(e) > 0 {
+			return e[len(e)-1].Position.Offset
+		}
+	}
+
+	return fallback
+}
+
+// nextLineIndex returns the index of the line following idx
+func nextLineIndex(content string, idx int) int {
+	lineEndIdx := nextIndexOf(content, "\n", max(0, min(idx, len(content))))
+	if lineEndIdx == -1 {
+		return len(content)
+	}
+
+	return lineEndIdx + 1
+}
+
+// nextLineStartingWith returns the index of the first token, on a line
+// following idx, which is one of starts. Used to resynchronize after errors.
+func nextLineStartingWith(content string, idx int, starts ...string) int {
+	lineIdx := nextLineIndex(content, idx)
+	for lineIdx < len(content) {
+		tokenIdx := firstValidTokenIndex(content, lineIdx)
+		if tokenIdx == -1 {
+			break
+		}
+
+		r := rune(content[tokenIdx])
+		var token Token
+		var err error
+		if unicode.IsLetter(r) {
+			token, _, err = popIdentifier(content, tokenIdx)
+		} else {
+			token, _, err = popSingleRune(content, tokenIdx)
+		}
+
+		if err == nil && slices.Contains(starts, token.Value) {
+			return tokenIdx
+		}
+
+		lineIdx = nextLineIndex(content, tokenIdx)
+	}
 
-	endIdx := idx + min(20, len(content)-idx)
-	return fmt.Errorf("[%d:%d] ...%s: %s", line, idx-lineStartIdx, content[idx:endIdx], errorMessage)
+	return len(content)
 }
@@ -0,0 +1,122 @@
+package stages
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/trudso/ginco/types"
+)
+
+// Pipeline generates code from .ginco files by parsing, validating,
+// transforming and emitting them, and writing the results to OutDir.
+//
+//	pipeline := stages.Pipeline{
+//		Inputs:       []string{"roleplaying.ginco"},
+//		FileEmitters: []stages.MetaFileEmitter{stages.GoStructEmitter{}},
+//		OutDir:       "generated",
+//	}
+//	written, err := pipeline.Run()
+type Pipeline struct {
+	// Inputs are the .ginco files to generate from, their imports are
+	// resolved relative to each of them
+	Inputs []string
+	// FileTransformers run before Transformers
+	FileTransformers []MetaFileTransformer
+	Transformers     []ModelTransformer
+	Emitters         []ModelEmitter
+	FileEmitters     []MetaFileEmitter
+	// OutDir is the directory the emitted paths are relative to
+	OutDir string
+}
+
+// Parse parses and validates the inputs
+func (self Pipeline) Parse() (types.MetaFile, error) {
+	file, err := GincoMetaFileParser{}.ParseFiles(self.Inputs...)
+	if err != nil {
+		return file, err
+	}
+
+	return file, ValidateMetaFile(file)
+}
+
+// Transform runs the file transformers followed by the model transformers
+func (self Pipeline) Transform(file types.MetaFile) (types.MetaFile, error) {
+	file, err := TransformMetaFile(file, self.FileTransformers)
+	if err != nil {
+		return file, err
+	}
+
+	return TransformModels(file, self.Transformers)
+}
+
+// Emit runs the model emitters for every model, followed by the file emitters
+func (self Pipeline) Emit(file types.MetaFile) ([]ModelEmitterResult, error) {
+	errs := []error{}
+	results, err := EmitModels(file, self.Emitters)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	emitted, err := EmitMetaFile(file, self.FileEmitters)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	results = append(results, emitted...)
+
+	written := map[string]bool{}
+	for _, result := range results {
+		if written[result.Path] {
+			errs = append(errs, fmt.Errorf("%s is emitted more than once", result.Path))
+		}
+		written[result.Path] = true
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// Generate parses, validates, transforms and emits the inputs
+// without writing anything
+func (self Pipeline) Generate() ([]ModelEmitterResult, error) {
+	file, err := self.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err = self.Transform(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.Emit(file)
+}
+
+// Run generates the results and writes them to OutDir,
+// returning the paths written
+func (self Pipeline) Run() ([]string, error) {
+	results, err := self.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	return self.Write(results)
+}
+
+func (self Pipeline) Write(results []ModelEmitterResult) ([]string, error) {
+	written := []string{}
+	for _, result := range results {
+		path := filepath.Join(self.OutDir, result.Path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return written, err
+		}
+
+		if err := os.WriteFile(path, []byte(result.Content), 0o644); err != nil {
+			return written, err
+		}
+
+		written = append(written, path)
+	}
+
+	return written, nil
+}
@@ -0,0 +1,102 @@
+package stages
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trudso/ginco/types"
+)
+
+// packageEmitter writes one file per package listing its models
+type packageEmitter struct{}
+
+func (self packageEmitter) GenerateFile(file types.MetaFile) ([]ModelEmitterResult, error) {
+	results := []ModelEmitterResult{}
+	for _, pkg := range file.Packages {
+		results = append(results, ModelEmitterResult{Path: pkg.Name + ".txt", Content: pkg.Name})
+	}
+
+	return results, nil
+}
+
+func TestPipelineRun(t *testing.T) {
+	dir := writeGincoFiles(t, map[string]string{
+		"roleplaying.ginco": `package roleplaying {
+	model Character {
+		fields {
+			=1 id uuid
+		}
+	}
+}`,
+		"horror.ginco": `import ./roleplaying.ginco
+
+package horror {
+	@inherits(roleplaying.Character)
+	model Vampire {
+		fields {
+			=1 clan string
+		}
+	}
+}`,
+	})
+
+	outDir := filepath.Join(dir, "out")
+	pipeline := Pipeline{
+		Inputs:           []string{filepath.Join(dir, "horror.ginco"), filepath.Join(dir, "roleplaying.ginco")},
+		FileTransformers: []MetaFileTransformer{InheritanceTransformer{}},
+		Transformers:     []ModelTransformer{suffixTransformer{"Dto"}},
+		Emitters:         []ModelEmitter{pathEmitter{[]string{"model.txt"}}},
+		FileEmitters:     []MetaFileEmitter{packageEmitter{}},
+		OutDir:           outDir,
+	}
+
+	written, err := pipeline.Run()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(outDir, "CharacterDto", "model.txt"),
+		filepath.Join(outDir, "VampireDto", "model.txt"),
+		filepath.Join(outDir, "roleplaying.txt"),
+		filepath.Join(outDir, "horror.txt"),
+	}, written)
+
+	content, err := os.ReadFile(filepath.Join(outDir, "VampireDto", "model.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "VampireDto", string(content))
+}
+
+func TestPipelineStopsOnErrors(t *testing.T) {
+	testCases := []struct {
+		content             string
+		expectedErrorValues []string
+	}{
+		{"package a {\n\tmodel A {\n\t\tfields {\n\t\t\t=1 $id uuid\n\t\t}\n\t}\n}", []string{"main.ginco:4:7: No identifier found"}},
+		{"package a {\n\tmodel A {\n\t\tfields {\n\t\t\t=1 id Missing\n\t\t}\n\t}\n}", []string{"main.ginco:4:10: Unresolved type a.Missing"}},
+		{"package a {\n\tmodel A {}\n\tmodel B {}\n}", []string{"A/same.txt is emitted more than once"}},
+	}
+
+	for _, tc := range testCases {
+		dir := writeGincoFiles(t, map[string]string{"main.ginco": tc.content})
+		outDir := filepath.Join(dir, "out")
+		pipeline := Pipeline{
+			Inputs:   []string{filepath.Join(dir, "main.ginco")},
+			Emitters: []ModelEmitter{sameEmitter{}},
+			OutDir:   outDir,
+		}
+
+		written, err := pipeline.Run()
+		assertErrorContains(t, err, tc.expectedErrorValues)
+		assert.Empty(t, written)
+
+		_, err = os.Stat(outDir)
+		assert.True(t, os.IsNotExist(err))
+	}
+}
+
+// sameEmitter writes the same path for every model
+type sameEmitter struct{}
+
+func (self sameEmitter) Generate(model types.MetaModel) ([]ModelEmitterResult, error) {
+	return []ModelEmitterResult{{Path: "A/same.txt"}}, nil
+}
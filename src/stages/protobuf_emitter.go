@@ -0,0 +1,231 @@
+package stages
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/trudso/ginco/types"
+)
+
+const (
+	PROTOBUF_WELL_KNOWN_PREFIX = "google.protobuf."
+	PROTOBUF_UNSPECIFIED       = "UNSPECIFIED"
+)
+
+/*
+	package roleplaying {
+		enum Clan {
+			literals {
+				Brujah
+			}
+		}
+
+		model Character {
+			fields {
+				=1 id uuid
+				=? clan Clan
+				=* tags string
+			}
+		}
+	}
+
+generates roleplaying.proto
+
+	syntax = "proto3";
+
+	package roleplaying;
+
+	enum Clan {
+	  CLAN_UNSPECIFIED = 0;
+	  CLAN_BRUJAH = 1;
+	}
+
+	message Character {
+	  string id = 1;
+	  optional Clan clan = 2;
+	  repeated string tags = 3;
+	}
+*/
+
+// ProtobufEmitter generates a proto3 file per package with a message per
+// model and an enum per enum. Field and literal numbers are taken from
+// Lock and the updated lock is emitted along with the proto files, so
+// regenerating never renumbers existing fields. Numbers of removed fields
+// and literals are reserved.
+type ProtobufEmitter struct {
+	// Lock holds the numbers assigned by previous runs, see ReadProtobufLock
+	Lock ProtobufLock
+	// GoPackage is the import path the Go code generated from the proto
+	// files lives under. Sets the go_package option when not empty.
+	GoPackage string
+
+	resolver typeResolver
+}
+
+func (self ProtobufEmitter) Name() string {
+	return "protobuf"
+}
+
+func (self ProtobufEmitter) GenerateFile(file types.MetaFile) ([]ModelEmitterResult, error) {
+	resolver, err := newTypeResolver(file)
+	if err != nil {
+		return []ModelEmitterResult{}, err
+	}
+	self.resolver = resolver
+
+	lock := self.Lock.clone()
+	results := []ModelEmitterResult{}
+	for _, pkg := range file.Packages {
+		results = append(results, ModelEmitterResult{Path: pkg.Name + ".proto", Content: self.generatePackage(pkg, lock)})
+	}
+
+	content, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return results, err
+	}
+
+	return append(results, ModelEmitterResult{Path: PROTOBUF_LOCK_FILE, Content: string(content) + "\n"}), nil
+}
+
+func (self ProtobufEmitter) generatePackage(pkg types.MetaPackage, lock ProtobufLock) string {
+	imports := map[string]bool{}
+	declarations := []string{}
+	for _, enum := range pkg.Enums {
+		declarations = append(declarations, self.enum(pkg.Name, enum, lock))
+	}
+
+	for _, model := range pkg.Models {
+		declarations = append(declarations, self.message(pkg.Name, model, lock, imports))
+	}
+
+	content := strings.Builder{}
+	fmt.Fprintf(&content, "%s\n\nsyntax = \"proto3\";\n\npackage %s;\n", GENERATED_HEADER, pkg.Name)
+	if self.GoPackage != "" {
+		fmt.Fprintf(&content, "\noption go_package = %q;\n", path.Join(self.GoPackage, goPackageName(pkg.Name)))
+	}
+
+	if len(imports) > 0 {
+		paths := []string{}
+		for imported := range imports {
+			paths = append(paths, imported)
+		}
+		slices.Sort(paths)
+
+		content.WriteString("\n")
+		for _, imported := range paths {
+			fmt.Fprintf(&content, "import %q;\n", imported)
+		}
+	}
+
+	for _, declaration := range declarations {
+		content.WriteString("\n" + declaration)
+	}
+
+	return content.String()
+}
+
+func (self ProtobufEmitter) enum(pkg string, enum types.MetaEnum, lock ProtobufLock) string {
+	name := qualifiedName(pkg, enum.Name)
+	numbers := assignNumbers(lockedNumbers(lock.Enums, name), enum.Literals)
+	prefix := strings.ToUpper(snakeCase(enum.Name)) + "_"
+
+	declaration := strings.Builder{}
+	fmt.Fprintf(&declaration, "enum %s {\n", enum.Name)
+	fmt.Fprintf(&declaration, "  %s%s = 0;\n", prefix, PROTOBUF_UNSPECIFIED)
+	writeProtobufReserved(&declaration, numbers, enum.Literals, func(literal string) string {
+		return prefix + strings.ToUpper(snakeCase(literal))
+	})
+	for _, literal := range enum.Literals {
+		fmt.Fprintf(&declaration, "  %s%s = %d;\n", prefix, strings.ToUpper(snakeCase(literal)), numbers[literal])
+	}
+	declaration.WriteString("}\n")
+
+	return declaration.String()
+}
+
+func (self ProtobufEmitter) message(pkg string, model types.MetaModel, lock ProtobufLock, imports map[string]bool) string {
+	names := []string{}
+	for _, field := range model.Fields {
+		names = append(names, field.Name)
+	}
+	numbers := assignNumbers(lockedNumbers(lock.Messages, qualifiedName(pkg, model.Name)), names)
+
+	declaration := strings.Builder{}
+	fmt.Fprintf(&declaration, "message %s {\n", model.Name)
+	writeProtobufReserved(&declaration, numbers, names, snakeCase)
+	for _, field := range model.Fields {
+		label := ""
+		switch field.Cardinality {
+		case types.ZeroOrOne:
+			label = "optional "
+		case types.Collection:
+			label = "repeated "
+		}
+
+		fieldType := self.typeName(pkg, field.Type, imports)
+		fmt.Fprintf(&declaration, "  %s%s %s = %d;\n", label, fieldType, snakeCase(field.Name), numbers[field.Name])
+	}
+	declaration.WriteString("}\n")
+
+	return declaration.String()
+}
+
+// typeName returns the protobuf type of metaType, adding the file
+// declaring it to imports
+func (self ProtobufEmitter) typeName(pkg string, metaType types.MetaType, imports map[string]bool) string {
+	if mapping, found := self.resolver.mapPrimitive(metaType, types.TargetProtobuf); found {
+		if wellKnown, found := strings.CutPrefix(mapping.Type, PROTOBUF_WELL_KNOWN_PREFIX); found {
+			imports["google/protobuf/"+snakeCase(wellKnown)+".proto"] = true
+		}
+		return mapping.Type
+	}
+
+	if metaType.Package == "" || metaType.Package == pkg {
+		return metaType.Name
+	}
+
+	imports[metaType.Package+".proto"] = true
+	return formatMetaType(metaType)
+}
+
+// lockedNumbers returns the numbers locked for name, adding them to locked
+// when there are none yet
+func lockedNumbers(locked map[string]map[string]int, name string) map[string]int {
+	if locked[name] == nil {
+		locked[name] = map[string]int{}
+	}
+
+	return locked[name]
+}
+
+// writeProtobufReserved reserves the numbers and names which are locked
+// but no longer declared
+func writeProtobufReserved(builder *strings.Builder, numbers map[string]int, declared []string, protobufName func(string) string) {
+	removed := []string{}
+	for name := range numbers {
+		if !slices.Contains(declared, name) {
+			removed = append(removed, name)
+		}
+	}
+
+	if len(removed) == 0 {
+		return
+	}
+
+	slices.SortFunc(removed, func(a, b string) int {
+		return numbers[a] - numbers[b]
+	})
+
+	reservedNumbers := []string{}
+	reservedNames := []string{}
+	for _, name := range removed {
+		reservedNumbers = append(reservedNumbers, fmt.Sprint(numbers[name]))
+		reservedNames = append(reservedNames, fmt.Sprintf("%q", protobufName(name)))
+	}
+
+	fmt.Fprintf(builder, "  reserved %s;\n", strings.Join(reservedNumbers, ", "))
+	fmt.Fprintf(builder, "  reserved %s;\n", strings.Join(reservedNames, ", "))
+}
@@ -0,0 +1,160 @@
+package stages
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProtobufEmitter(t *testing.T) {
+	file := parseGinco(t, `package roleplaying {
+	enum Clan {
+		literals {
+			Brujah
+			Ventrue
+		}
+	}
+
+	model Character {
+		fields {
+			=1 id uuid
+			=? clan Clan
+			=* tags string
+			=1 born datetime
+		}
+	}
+}
+
+package horror {
+	model Vampire {
+		fields {
+			-1 sire roleplaying.Character
+			=* clans roleplaying.Clan
+		}
+	}
+}`)
+
+	results, err := ProtobufEmitter{GoPackage: "example.com/proto"}.GenerateFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"roleplaying.proto", "horror.proto", PROTOBUF_LOCK_FILE}, resultPaths(results))
+	assert.Equal(t, `// Code generated by ginco. DO NOT EDIT.
+
+syntax = "proto3";
+
+package roleplaying;
+
+option go_package = "example.com/proto/roleplaying";
+
+import "google/protobuf/timestamp.proto";
+
+enum Clan {
+  CLAN_UNSPECIFIED = 0;
+  CLAN_BRUJAH = 1;
+  CLAN_VENTRUE = 2;
+}
+
+message Character {
+  string id = 1;
+  optional Clan clan = 2;
+  repeated string tags = 3;
+  google.protobuf.Timestamp born = 4;
+}
+`, results[0].Content)
+	assert.Equal(t, `// Code generated by ginco. DO NOT EDIT.
+
+syntax = "proto3";
+
+package horror;
+
+option go_package = "example.com/proto/horror";
+
+import "roleplaying.proto";
+
+message Vampire {
+  roleplaying.Character sire = 1;
+  repeated roleplaying.Clan clans = 2;
+}
+`, results[1].Content)
+
+	lock := ProtobufLock{}
+	assert.NoError(t, json.Unmarshal([]byte(results[2].Content), &lock))
+	assert.Equal(t, map[string]int{"id": 1, "clan": 2, "tags": 3, "born": 4}, lock.Messages["roleplaying.Character"])
+	assert.Equal(t, map[string]int{"Brujah": 1, "Ventrue": 2}, lock.Enums["roleplaying.Clan"])
+}
+
+func TestProtobufEmitterLock(t *testing.T) {
+	file := parseGinco(t, `package roleplaying {
+	enum Clan {
+		literals {
+			Ventrue
+			Tremere
+		}
+	}
+
+	model Character {
+		fields {
+			=1 name string
+			=1 id uuid
+			=1 level int32
+		}
+	}
+}`)
+
+	lock := ProtobufLock{
+		Messages: map[string]map[string]int{
+			"roleplaying.Character": {"id": 1, "nickname": 2, "name": 3},
+		},
+		Enums: map[string]map[string]int{
+			"roleplaying.Clan": {"Brujah": 1, "Ventrue": 2},
+		},
+	}
+
+	results, err := ProtobufEmitter{Lock: lock}.GenerateFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, `// Code generated by ginco. DO NOT EDIT.
+
+syntax = "proto3";
+
+package roleplaying;
+
+enum Clan {
+  CLAN_UNSPECIFIED = 0;
+  reserved 1;
+  reserved "CLAN_BRUJAH";
+  CLAN_VENTRUE = 2;
+  CLAN_TREMERE = 3;
+}
+
+message Character {
+  reserved 2;
+  reserved "nickname";
+  string name = 3;
+  string id = 1;
+  int32 level = 4;
+}
+`, results[0].Content)
+
+	// the lock passed in is left untouched
+	assert.Equal(t, map[string]int{"id": 1, "nickname": 2, "name": 3}, lock.Messages["roleplaying.Character"])
+}
+
+func TestAssignNumbersSkipsReservedRange(t *testing.T) {
+	numbers := assignNumbers(map[string]int{"a": PROTOBUF_RESERVED_FROM - 1}, []string{"a", "b"})
+	assert.Equal(t, map[string]int{"a": PROTOBUF_RESERVED_FROM - 1, "b": PROTOBUF_RESERVED_TO + 1}, numbers)
+}
+
+func TestReadProtobufLock(t *testing.T) {
+	dir := t.TempDir()
+	lock, err := ReadProtobufLock(filepath.Join(dir, PROTOBUF_LOCK_FILE))
+	assert.NoError(t, err)
+	assert.Empty(t, lock.Messages)
+
+	path := filepath.Join(dir, PROTOBUF_LOCK_FILE)
+	assert.NoError(t, os.WriteFile(path, []byte(`{"messages": {"roleplaying.Character": {"id": 1}}}`), 0o644))
+	lock, err = ReadProtobufLock(path)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"id": 1}, lock.Messages["roleplaying.Character"])
+}
@@ -0,0 +1,81 @@
+package stages
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"maps"
+	"os"
+)
+
+const (
+	PROTOBUF_LOCK_FILE = "proto.lock.json"
+	// field numbers reserved by the protobuf implementation
+	PROTOBUF_RESERVED_FROM = 19000
+	PROTOBUF_RESERVED_TO   = 19999
+)
+
+// ProtobufLock records the number assigned to every message field and
+// enum literal by qualified message or enum name. Numbers of removed
+// fields and literals are kept so they are never reused.
+type ProtobufLock struct {
+	Messages map[string]map[string]int `json:"messages"`
+	Enums    map[string]map[string]int `json:"enums"`
+}
+
+// ReadProtobufLock reads the lock at path. A missing lock is empty.
+func ReadProtobufLock(path string) (ProtobufLock, error) {
+	lock := ProtobufLock{}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return lock, nil
+	}
+
+	if err != nil {
+		return lock, err
+	}
+
+	err = json.Unmarshal(content, &lock)
+	return lock, err
+}
+
+func (self ProtobufLock) clone() ProtobufLock {
+	cloned := ProtobufLock{
+		Messages: map[string]map[string]int{},
+		Enums:    map[string]map[string]int{},
+	}
+
+	for name, numbers := range self.Messages {
+		cloned.Messages[name] = maps.Clone(numbers)
+	}
+
+	for name, numbers := range self.Enums {
+		cloned.Enums[name] = maps.Clone(numbers)
+	}
+
+	return cloned
+}
+
+// assignNumbers returns the numbers of names, keeping the numbers already
+// in locked and numbering new names after the highest one ever assigned
+func assignNumbers(locked map[string]int, names []string) map[string]int {
+	next := 1
+	for _, number := range locked {
+		next = max(next, number+1)
+	}
+
+	for _, name := range names {
+		if _, found := locked[name]; found {
+			continue
+		}
+
+		if next >= PROTOBUF_RESERVED_FROM && next <= PROTOBUF_RESERVED_TO {
+			next = PROTOBUF_RESERVED_TO + 1
+		}
+
+		locked[name] = next
+		next++
+	}
+
+	return locked
+}
@@ -0,0 +1,453 @@
+package stages
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/trudso/ginco/types"
+)
+
+const (
+	TABLE_TRAIT      = "table"
+	SQL_KEY_COLUMN   = "id"
+	SQL_VALUE_COLUMN = "value"
+)
+
+/*
+	package roleplaying {
+		@table(name="characters")
+		model Character {
+			fields {
+				=1 id uuid
+				=1 clan Clan
+				-? weapon Item
+				=* tags string
+				-* items Item
+			}
+		}
+	}
+
+generates sqlite.sql
+
+	CREATE TABLE "characters" (
+	  "id" TEXT PRIMARY KEY,
+	  "clan" TEXT NOT NULL CHECK ("clan" IN ('Brujah', 'Ventrue')),
+	  "weapon_id" INTEGER,
+	  FOREIGN KEY ("weapon_id") REFERENCES "roleplaying_item" ("id")
+	);
+
+	CREATE TABLE "characters_tags" (
+	  "character_id" TEXT NOT NULL,
+	  "value" TEXT NOT NULL,
+	  FOREIGN KEY ("character_id") REFERENCES "characters" ("id") ON DELETE CASCADE
+	);
+
+	CREATE TABLE "characters_items" (
+	  ...
+	  PRIMARY KEY ("character_id", "item_id"),
+	  ...
+	);
+*/
+
+// SQLEmitter generates the CREATE TABLE statements of every model in a
+// single file per dialect. Tables are named <package>_<model> unless
+// overridden with @table(name="..."). Fields map to the tables like so:
+//   - fields with cardinality One are NOT NULL
+//   - a model keyed by a field "id" of cardinality One uses it as primary
+//     key, other models get a generated "id"
+//   - single references to models are foreign keys
+//   - composed collections of models add a foreign key to the parent to
+//     the table of the composed model, deleted along with the parent
+//   - aggregated collections of models are stored in join tables
+//   - collections of primitives and enums are stored in child tables
+//   - enums are enum types in Postgres and CHECK constraints in SQLite
+type SQLEmitter struct {
+	// Dialect is either types.TargetPostgres or types.TargetSQLite
+	Dialect types.Target
+}
+
+type sqlDialect struct {
+	// keyType is the type of generated primary keys
+	keyType string
+	// identity is the column definition of generated primary keys
+	identity string
+	// enumTypes creates an enum type per enum instead of CHECK constraints
+	enumTypes bool
+	// alterForeignKeys adds foreign keys to tables created later with
+	// ALTER TABLE instead of declaring them inline
+	alterForeignKeys bool
+}
+
+var sqlDialects = map[types.Target]sqlDialect{
+	types.TargetPostgres: {
+		keyType:          "BIGINT",
+		identity:         "BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY",
+		enumTypes:        true,
+		alterForeignKeys: true,
+	},
+	types.TargetSQLite: {
+		keyType:  "INTEGER",
+		identity: "INTEGER PRIMARY KEY AUTOINCREMENT",
+	},
+}
+
+type sqlTable struct {
+	Name string
+	// Source is the model the table is generated for
+	Source      string
+	Columns     []sqlColumn
+	PrimaryKey  []string
+	ForeignKeys []sqlForeignKey
+}
+
+type sqlColumn struct {
+	Name string
+	// Definition is the type followed by its constraints
+	Definition string
+}
+
+type sqlForeignKey struct {
+	Column   string
+	Table    string
+	OnDelete string
+}
+
+// sqlSchema collects the tables of a MetaFile
+type sqlSchema struct {
+	dialect  sqlDialect
+	target   types.Target
+	resolver typeResolver
+	tables   map[string]*sqlTable
+	order    []string
+}
+
+func (self SQLEmitter) Name() string {
+	return "sql-" + string(self.Dialect)
+}
+
+func (self SQLEmitter) GenerateFile(file types.MetaFile) ([]ModelEmitterResult, error) {
+	dialect, found := sqlDialects[self.Dialect]
+	if !found {
+		return []ModelEmitterResult{}, fmt.Errorf("unsupported SQL dialect %q, expected %s or %s", self.Dialect, types.TargetPostgres, types.TargetSQLite)
+	}
+
+	resolver, err := newTypeResolver(file)
+	if err != nil {
+		return []ModelEmitterResult{}, err
+	}
+
+	schema := sqlSchema{
+		dialect:  dialect,
+		target:   self.Dialect,
+		resolver: resolver,
+		tables:   map[string]*sqlTable{},
+	}
+
+	for _, pkg := range file.Packages {
+		for _, model := range pkg.Models {
+			err := schema.addTable(&sqlTable{
+				Name:    sqlTableName(model),
+				Source:  qualifiedName(pkg.Name, model.Name),
+				Columns: []sqlColumn{schema.keyColumn(model)},
+			})
+			if err != nil {
+				return []ModelEmitterResult{}, fmt.Errorf("model %s: %w", qualifiedName(pkg.Name, model.Name), err)
+			}
+		}
+	}
+
+	for _, pkg := range file.Packages {
+		for _, model := range pkg.Models {
+			if err := schema.addFields(model); err != nil {
+				return []ModelEmitterResult{}, fmt.Errorf("model %s: %w", qualifiedName(pkg.Name, model.Name), err)
+			}
+		}
+	}
+
+	content := strings.Builder{}
+	content.WriteString("-- Code generated by ginco. DO NOT EDIT.\n")
+	if dialect.enumTypes {
+		for _, pkg := range file.Packages {
+			for _, enum := range pkg.Enums {
+				fmt.Fprintf(&content, "\nCREATE TYPE %s AS ENUM (%s);\n", sqlIdentifier(sqlEnumName(pkg.Name, enum.Name)), sqlStrings(enum.Literals))
+			}
+		}
+	}
+	schema.write(&content)
+
+	return []ModelEmitterResult{{Path: string(self.Dialect) + ".sql", Content: content.String()}}, nil
+}
+
+// addTable adds table, failing when another table has the same name
+func (self *sqlSchema) addTable(table *sqlTable) error {
+	if existing, found := self.tables[table.Name]; found {
+		return fmt.Errorf("table %s of %s clashes with the table of %s", table.Name, table.Source, existing.Source)
+	}
+
+	self.tables[table.Name] = table
+	self.order = append(self.order, table.Name)
+	return nil
+}
+
+// addColumn adds column, failing when the table already has a column of
+// the same name
+func (self *sqlTable) addColumn(column sqlColumn) error {
+	for _, existing := range self.Columns {
+		if existing.Name == column.Name {
+			return fmt.Errorf("column %s clashes with another column of table %s", column.Name, self.Name)
+		}
+	}
+
+	self.Columns = append(self.Columns, column)
+	return nil
+}
+
+// sqlTableName returns the table of model
+func sqlTableName(model types.MetaModel) string {
+	if trait, found := types.FindTrait(model.Traits, TABLE_TRAIT); found {
+		if argument, found := trait.Argument("name"); found {
+			return argument.Value
+		}
+	}
+
+	return snakeCase(model.Package) + "_" + snakeCase(model.Name)
+}
+
+func sqlEnumName(pkg, name string) string {
+	return snakeCase(pkg) + "_" + snakeCase(name)
+}
+
+// keyField returns the field used as primary key of model
+func (self sqlSchema) keyField(model types.MetaModel) (types.MetaModelField, bool) {
+	for _, field := range model.Fields {
+		if field.Name == SQL_KEY_COLUMN && field.Cardinality == types.One && self.resolver.kind(field.Type) == primitiveKind {
+			return field, true
+		}
+	}
+
+	return types.MetaModelField{}, false
+}
+
+func (self sqlSchema) keyColumn(model types.MetaModel) sqlColumn {
+	if field, found := self.keyField(model); found {
+		return sqlColumn{Name: SQL_KEY_COLUMN, Definition: self.primitiveType(field.Type) + " PRIMARY KEY"}
+	}
+
+	return sqlColumn{Name: SQL_KEY_COLUMN, Definition: self.dialect.identity}
+}
+
+// keyType returns the type of foreign keys referencing model
+func (self sqlSchema) keyType(model types.MetaModel) string {
+	if field, found := self.keyField(model); found {
+		return self.primitiveType(field.Type)
+	}
+
+	return self.dialect.keyType
+}
+
+func (self sqlSchema) primitiveType(metaType types.MetaType) string {
+	mapping, _ := self.resolver.mapPrimitive(metaType, self.target)
+	return mapping.Type
+}
+
+// valueDefinition returns the definition of a column holding a primitive or enum
+func (self sqlSchema) valueDefinition(column string, metaType types.MetaType, notNull bool) string {
+	definition := self.primitiveType(metaType)
+	check := ""
+	if enum, found := self.resolver.enum(metaType); found {
+		if self.dialect.enumTypes {
+			definition = sqlIdentifier(sqlEnumName(metaType.Package, enum.Name))
+		} else {
+			definition = "TEXT"
+			check = fmt.Sprintf(" CHECK (%s IN (%s))", sqlIdentifier(column), sqlStrings(enum.Literals))
+		}
+	}
+
+	if notNull {
+		definition += " NOT NULL"
+	}
+
+	return definition + check
+}
+
+func (self *sqlSchema) addFields(model types.MetaModel) error {
+	table := self.tables[sqlTableName(model)]
+	parentColumn := snakeCase(model.Name) + "_" + SQL_KEY_COLUMN
+	parentKey := sqlForeignKey{Column: parentColumn, Table: table.Name, OnDelete: "CASCADE"}
+
+	key, hasKey := self.keyField(model)
+	for _, field := range model.Fields {
+		if hasKey && field.Name == key.Name {
+			continue
+		}
+
+		column := snakeCase(field.Name)
+		kind := self.resolver.kind(field.Type)
+		if kind != modelKind {
+			if field.Cardinality != types.Collection {
+				if column == SQL_KEY_COLUMN {
+					return fmt.Errorf("field %s clashes with the generated %s column, only a =1 field of a primitive type can be the key", field.Name, SQL_KEY_COLUMN)
+				}
+
+				err := table.addColumn(sqlColumn{column, self.valueDefinition(column, field.Type, field.Cardinality == types.One)})
+				if err != nil {
+					return fmt.Errorf("field %s: %w", field.Name, err)
+				}
+				continue
+			}
+
+			err := self.addTable(&sqlTable{
+				Name:   table.Name + "_" + column,
+				Source: table.Source + "." + field.Name,
+				Columns: []sqlColumn{
+					{parentColumn, self.keyType(model) + " NOT NULL"},
+					{SQL_VALUE_COLUMN, self.valueDefinition(SQL_VALUE_COLUMN, field.Type, true)},
+				},
+				ForeignKeys: []sqlForeignKey{parentKey},
+			})
+			if err != nil {
+				return fmt.Errorf("field %s: %w", field.Name, err)
+			}
+			continue
+		}
+
+		target, found := self.resolver.model(field.Type)
+		if !found {
+			return fmt.Errorf("field %s: unknown model %s", field.Name, formatMetaType(field.Type))
+		}
+		targetTable := self.tables[sqlTableName(target)]
+
+		switch {
+		case field.Cardinality != types.Collection:
+			column += "_" + SQL_KEY_COLUMN
+			definition := self.keyType(target)
+			if field.Cardinality == types.One {
+				definition += " NOT NULL"
+			}
+
+			if err := table.addColumn(sqlColumn{column, definition}); err != nil {
+				return fmt.Errorf("field %s: %w", field.Name, err)
+			}
+			table.ForeignKeys = append(table.ForeignKeys, sqlForeignKey{Column: column, Table: targetTable.Name})
+		case field.Ownership == types.Composition:
+			// the composed models belong to the parent
+			column = snakeCase(model.Name) + "_" + column + "_" + SQL_KEY_COLUMN
+			if err := targetTable.addColumn(sqlColumn{column, self.keyType(model)}); err != nil {
+				return fmt.Errorf("field %s: %w", field.Name, err)
+			}
+			targetTable.ForeignKeys = append(targetTable.ForeignKeys, sqlForeignKey{Column: column, Table: table.Name, OnDelete: "CASCADE"})
+		default:
+			targetColumn := snakeCase(target.Name) + "_" + SQL_KEY_COLUMN
+			if targetColumn == parentColumn {
+				targetColumn = column + "_" + SQL_KEY_COLUMN
+			}
+
+			err := self.addTable(&sqlTable{
+				Name:   table.Name + "_" + column,
+				Source: table.Source + "." + field.Name,
+				Columns: []sqlColumn{
+					{parentColumn, self.keyType(model) + " NOT NULL"},
+					{targetColumn, self.keyType(target) + " NOT NULL"},
+				},
+				PrimaryKey: []string{parentColumn, targetColumn},
+				ForeignKeys: []sqlForeignKey{
+					parentKey,
+					{Column: targetColumn, Table: targetTable.Name, OnDelete: "CASCADE"},
+				},
+			})
+			if err != nil {
+				return fmt.Errorf("field %s: %w", field.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// sortedTables returns the tables in declaration order, moving tables
+// after the tables they reference where there are no cycles
+func (self sqlSchema) sortedTables() []*sqlTable {
+	sorted := []*sqlTable{}
+	visited := map[string]bool{}
+	var visit func(name string)
+	visit = func(name string) {
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+
+		table := self.tables[name]
+		for _, foreignKey := range table.ForeignKeys {
+			visit(foreignKey.Table)
+		}
+		sorted = append(sorted, table)
+	}
+
+	for _, name := range self.order {
+		visit(name)
+	}
+
+	return sorted
+}
+
+func (self sqlSchema) write(builder *strings.Builder) {
+	created := map[string]bool{}
+	altered := []string{}
+	for _, table := range self.sortedTables() {
+		created[table.Name] = true
+		definitions := []string{}
+		for _, column := range table.Columns {
+			definitions = append(definitions, sqlIdentifier(column.Name)+" "+column.Definition)
+		}
+
+		if len(table.PrimaryKey) > 0 {
+			definitions = append(definitions, fmt.Sprintf("PRIMARY KEY (%s)", sqlIdentifiers(table.PrimaryKey)))
+		}
+
+		for _, foreignKey := range table.ForeignKeys {
+			if self.dialect.alterForeignKeys && !created[foreignKey.Table] {
+				altered = append(altered, fmt.Sprintf("ALTER TABLE %s ADD %s;", sqlIdentifier(table.Name), foreignKey))
+				continue
+			}
+
+			definitions = append(definitions, foreignKey.String())
+		}
+
+		fmt.Fprintf(builder, "\n-- %s\nCREATE TABLE %s (\n  %s\n);\n", table.Source, sqlIdentifier(table.Name), strings.Join(definitions, ",\n  "))
+	}
+
+	if len(altered) > 0 {
+		fmt.Fprintf(builder, "\n%s\n", strings.Join(altered, "\n"))
+	}
+}
+
+func (self sqlForeignKey) String() string {
+	foreignKey := fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)", sqlIdentifier(self.Column), sqlIdentifier(self.Table), sqlIdentifier(SQL_KEY_COLUMN))
+	if self.OnDelete != "" {
+		foreignKey += " ON DELETE " + self.OnDelete
+	}
+
+	return foreignKey
+}
+
+func sqlIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func sqlIdentifiers(names []string) string {
+	quoted := []string{}
+	for _, name := range names {
+		quoted = append(quoted, sqlIdentifier(name))
+	}
+
+	return strings.Join(quoted, ", ")
+}
+
+func sqlStrings(values []string) string {
+	quoted := []string{}
+	for _, value := range values {
+		quoted = append(quoted, "'"+strings.ReplaceAll(value, "'", "''")+"'")
+	}
+
+	return strings.Join(quoted, ", ")
+}
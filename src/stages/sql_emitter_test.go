@@ -0,0 +1,200 @@
+package stages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trudso/ginco/types"
+)
+
+const SQL_GINCO = `package roleplaying {
+	enum Clan {
+		literals {
+			Brujah
+			Ventrue
+		}
+	}
+
+	model Item {
+		fields {
+			=1 name string
+		}
+	}
+
+	@table(name="characters")
+	model Character {
+		fields {
+			=1 id uuid
+			=1 clan Clan
+			-? weapon Item
+			=* tags string
+			=* inventory Item
+			-* loot Item
+		}
+	}
+}`
+
+func TestSQLEmitterPostgres(t *testing.T) {
+	file := parseGinco(t, SQL_GINCO)
+
+	results, err := SQLEmitter{Dialect: types.TargetPostgres}.GenerateFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"postgres.sql"}, resultPaths(results))
+	assert.Equal(t, `-- Code generated by ginco. DO NOT EDIT.
+
+CREATE TYPE "roleplaying_clan" AS ENUM ('Brujah', 'Ventrue');
+
+-- roleplaying.Character
+CREATE TABLE "characters" (
+  "id" UUID PRIMARY KEY,
+  "clan" "roleplaying_clan" NOT NULL,
+  "weapon_id" BIGINT
+);
+
+-- roleplaying.Item
+CREATE TABLE "roleplaying_item" (
+  "id" BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+  "name" TEXT NOT NULL,
+  "character_inventory_id" UUID,
+  FOREIGN KEY ("character_inventory_id") REFERENCES "characters" ("id") ON DELETE CASCADE
+);
+
+-- roleplaying.Character.tags
+CREATE TABLE "characters_tags" (
+  "character_id" UUID NOT NULL,
+  "value" TEXT NOT NULL,
+  FOREIGN KEY ("character_id") REFERENCES "characters" ("id") ON DELETE CASCADE
+);
+
+-- roleplaying.Character.loot
+CREATE TABLE "characters_loot" (
+  "character_id" UUID NOT NULL,
+  "item_id" BIGINT NOT NULL,
+  PRIMARY KEY ("character_id", "item_id"),
+  FOREIGN KEY ("character_id") REFERENCES "characters" ("id") ON DELETE CASCADE,
+  FOREIGN KEY ("item_id") REFERENCES "roleplaying_item" ("id") ON DELETE CASCADE
+);
+
+ALTER TABLE "characters" ADD FOREIGN KEY ("weapon_id") REFERENCES "roleplaying_item" ("id");
+`, results[0].Content)
+}
+
+func TestSQLEmitterSQLite(t *testing.T) {
+	file := parseGinco(t, SQL_GINCO)
+
+	results, err := SQLEmitter{Dialect: types.TargetSQLite}.GenerateFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sqlite.sql"}, resultPaths(results))
+	assert.Contains(t, results[0].Content, `CREATE TABLE "roleplaying_item" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "name" TEXT NOT NULL,
+  "character_inventory_id" TEXT,
+  FOREIGN KEY ("character_inventory_id") REFERENCES "characters" ("id") ON DELETE CASCADE
+);`)
+	assert.Contains(t, results[0].Content, `"clan" TEXT NOT NULL CHECK ("clan" IN ('Brujah', 'Ventrue')),`)
+	assert.NotContains(t, results[0].Content, "CREATE TYPE")
+	assert.NotContains(t, results[0].Content, "ALTER TABLE")
+}
+
+func TestSQLEmitterSelfReference(t *testing.T) {
+	file := parseGinco(t, `package roleplaying {
+	model Character {
+		fields {
+			-* friends Character
+		}
+	}
+}`)
+
+	results, err := SQLEmitter{Dialect: types.TargetSQLite}.GenerateFile(file)
+	assert.NoError(t, err)
+	assert.Contains(t, results[0].Content, `CREATE TABLE "roleplaying_character_friends" (
+  "character_id" INTEGER NOT NULL,
+  "friends_id" INTEGER NOT NULL,
+  PRIMARY KEY ("character_id", "friends_id"),`)
+}
+
+func TestSQLEmitterUnknownDialect(t *testing.T) {
+	_, err := SQLEmitter{Dialect: types.TargetGo}.GenerateFile(types.MetaFile{})
+	assertErrorContains(t, err, []string{`unsupported SQL dialect "go"`})
+}
+
+func TestSQLEmitterKeyClash(t *testing.T) {
+	testCases := []struct {
+		field               string
+		expectedErrorValues []string
+	}{
+		{"=1 id uuid", nil},
+		{"=* id uuid", nil},
+		{"=? id uuid", []string{"model roleplaying.Character: field id clashes with the generated id column"}},
+		{"=1 id Clan", []string{"model roleplaying.Character: field id clashes with the generated id column"}},
+	}
+
+	for _, tc := range testCases {
+		file := parseGinco(t, `package roleplaying {
+	enum Clan {
+		literals {
+			Brujah
+		}
+	}
+
+	model Character {
+		fields {
+			`+tc.field+`
+		}
+	}
+}`)
+
+		_, err := SQLEmitter{Dialect: types.TargetPostgres}.GenerateFile(file)
+		assertErrorContains(t, err, tc.expectedErrorValues)
+	}
+}
+
+func TestSQLEmitterNameClash(t *testing.T) {
+	testCases := []struct {
+		content             string
+		expectedErrorValues []string
+	}{
+		{`package roleplaying {
+	@table(name="characters")
+	model Character {}
+
+	@table(name="characters")
+	model Vampire {}
+}`, []string{"model roleplaying.Vampire: table characters of roleplaying.Vampire clashes with the table of roleplaying.Character"}},
+		{`package shop {
+	model Item {}
+
+	model Order {
+		fields {
+			-* items Item
+		}
+	}
+
+	model OrderItems {}
+}`, []string{"model shop.Order: field items: table shop_order_items of shop.Order.items clashes with the table of shop.OrderItems"}},
+		{`package shared {
+	model Skill {}
+}
+
+package a {
+	model Character {
+		fields {
+			=* skills shared.Skill
+		}
+	}
+}
+
+package b {
+	model Character {
+		fields {
+			=* skills shared.Skill
+		}
+	}
+}`, []string{"model b.Character: field skills: column character_skills_id clashes with another column of table shared_skill"}},
+	}
+
+	for _, tc := range testCases {
+		_, err := SQLEmitter{Dialect: types.TargetPostgres}.GenerateFile(parseGinco(t, tc.content))
+		assertErrorContains(t, err, tc.expectedErrorValues)
+	}
+}
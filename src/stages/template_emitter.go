@@ -0,0 +1,275 @@
+package stages
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/trudso/ginco/types"
+)
+
+const (
+	TEMPLATE_EXTENSION         = ".tmpl"
+	TEMPLATE_PACKAGE_EXTENSION = ".package.tmpl"
+	TEMPLATE_PARTIAL_PREFIX    = "_"
+)
+
+/*
+	{{- output (printf "%s/%s.ts" .Package.Name (kebab .Model.Name)) -}}
+	export interface {{.Model.Name}} {
+	{{- range .Model.Fields}}
+	  {{camel .Name}}{{if isOptional .}}?{{end}}: {{mapType .Type "typescript"}}{{if isCollection .}}[]{{end}};
+	{{- end}}
+	}
+*/
+
+// TemplateEmitter runs the text/template files in a directory for every
+// model. Templates are given a TemplateData and decide where their output
+// goes by calling output with a path, everything written after the call
+// ends up in that file. A template writing nothing generates nothing.
+//
+// Templates ending in .package.tmpl run once per package instead, and
+// templates starting with _ are only executed when included from others.
+// Besides the built-in functions of text/template, templates can use:
+//   - pascal, camel, snake, kebab, upper and lower to convert names and
+//     join to join them
+//   - plural and singular to inflect english names
+//   - mapType to map a type to a target, e.g. mapType .Type "go"
+//   - isPrimitive, isEnum, isModel, enum and model to resolve types
+//   - isOptional, isOne, isCollection, isComposition and isAggregation
+//     to inspect fields
+//   - trait, hasTrait and traitArg to look up traits, e.g.
+//     traitArg .Model.Traits "table" "name"
+type TemplateEmitter struct {
+	templates *template.Template
+	names     []string
+	file      types.MetaFile
+	resolver  typeResolver
+}
+
+// TemplateData is passed to the templates. Model is empty in package templates.
+type TemplateData struct {
+	File    types.MetaFile
+	Package types.MetaPackage
+	Model   types.MetaModel
+}
+
+// NewTemplateEmitter loads the templates in dir
+func NewTemplateEmitter(dir string) (TemplateEmitter, error) {
+	emitter := TemplateEmitter{}
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+TEMPLATE_EXTENSION))
+	if err != nil {
+		return emitter, err
+	}
+
+	if len(paths) == 0 {
+		return emitter, fmt.Errorf("no %s templates found in %s", TEMPLATE_EXTENSION, dir)
+	}
+
+	templates, err := template.New(filepath.Base(paths[0])).Funcs(emitter.funcs(nil)).ParseFiles(paths...)
+	if err != nil {
+		return emitter, err
+	}
+
+	emitter.templates = templates
+	for _, path := range paths {
+		if name := filepath.Base(path); !strings.HasPrefix(name, TEMPLATE_PARTIAL_PREFIX) {
+			emitter.names = append(emitter.names, name)
+		}
+	}
+	slices.Sort(emitter.names)
+
+	return emitter, nil
+}
+
+func (self TemplateEmitter) Name() string {
+	return "template"
+}
+
+func (self TemplateEmitter) forFile(file types.MetaFile) (TemplateEmitter, error) {
+	resolver, err := newTypeResolver(file)
+	self.file = file
+	self.resolver = resolver
+	return self, err
+}
+
+// GenerateFile runs the model templates for every model and the package
+// templates for every package of file
+func (self TemplateEmitter) GenerateFile(file types.MetaFile) ([]ModelEmitterResult, error) {
+	emitter, err := self.forFile(file)
+	if err != nil {
+		return []ModelEmitterResult{}, err
+	}
+
+	results, err := generateModels(file, emitter)
+	if err != nil {
+		return results, err
+	}
+
+	for _, pkg := range file.Packages {
+		for _, name := range self.names {
+			if !strings.HasSuffix(name, TEMPLATE_PACKAGE_EXTENSION) {
+				continue
+			}
+
+			emitted, err := emitter.execute(name, TemplateData{File: file, Package: pkg})
+			if err != nil {
+				return results, fmt.Errorf("package %s: %w", pkg.Name, err)
+			}
+			results = append(results, emitted...)
+		}
+	}
+
+	return results, nil
+}
+
+func (self TemplateEmitter) Generate(model types.MetaModel) ([]ModelEmitterResult, error) {
+	if err := self.resolver.resolving("generate the models with GenerateFile"); err != nil {
+		return []ModelEmitterResult{}, err
+	}
+
+	data := TemplateData{File: self.file, Package: types.MetaPackage{Name: model.Package}, Model: model}
+	for _, pkg := range self.file.Packages {
+		if pkg.Name == model.Package {
+			data.Package = pkg
+		}
+	}
+
+	results := []ModelEmitterResult{}
+	for _, name := range self.names {
+		if strings.HasSuffix(name, TEMPLATE_PACKAGE_EXTENSION) {
+			continue
+		}
+
+		emitted, err := self.execute(name, data)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, emitted...)
+	}
+
+	return results, nil
+}
+
+// execute runs the template called name and splits its output into a
+// result per call to output
+func (self TemplateEmitter) execute(name string, data TemplateData) ([]ModelEmitterResult, error) {
+	if self.templates == nil {
+		return []ModelEmitterResult{}, fmt.Errorf("no templates loaded, use NewTemplateEmitter")
+	}
+
+	type output struct {
+		path  string
+		start int
+	}
+	outputs := []output{}
+	content := bytes.Buffer{}
+	templates, err := self.templates.Clone()
+	if err != nil {
+		return []ModelEmitterResult{}, err
+	}
+
+	templates.Funcs(self.funcs(func(path string) string {
+		outputs = append(outputs, output{path: path, start: content.Len()})
+		return ""
+	}))
+
+	if err := templates.ExecuteTemplate(&content, name, data); err != nil {
+		return []ModelEmitterResult{}, err
+	}
+
+	results := []ModelEmitterResult{}
+	written := content.String()
+	unassigned := written
+	if len(outputs) > 0 {
+		unassigned = written[:outputs[0].start]
+	}
+
+	if strings.TrimSpace(unassigned) != "" {
+		return results, fmt.Errorf("template %s writes content before calling output", name)
+	}
+
+	for i, output := range outputs {
+		end := len(written)
+		if i+1 < len(outputs) {
+			end = outputs[i+1].start
+		}
+
+		results = append(results, ModelEmitterResult{Path: output.path, Content: written[output.start:end]})
+	}
+
+	return results, nil
+}
+
+func (self TemplateEmitter) funcs(output func(string) string) template.FuncMap {
+	return template.FuncMap{
+		"output": output,
+		"pascal": pascalCase,
+		"camel":  camelCase,
+		"snake":  snakeCase,
+		"kebab": func(name string) string {
+			return strings.Join(splitWords(name), "-")
+		},
+		"upper":    strings.ToUpper,
+		"lower":    strings.ToLower,
+		"join":     strings.Join,
+		"plural":   pluralize,
+		"singular": singularize,
+		"mapType": func(metaType types.MetaType, target string) string {
+			if mapping, found := self.resolver.mapPrimitive(metaType, types.Target(target)); found {
+				return mapping.Type
+			}
+			return metaType.Name
+		},
+		"isPrimitive": func(metaType types.MetaType) bool {
+			return self.resolver.kind(metaType) == primitiveKind
+		},
+		"isEnum": func(metaType types.MetaType) bool {
+			return self.resolver.kind(metaType) == enumKind
+		},
+		"isModel": func(metaType types.MetaType) bool {
+			return self.resolver.kind(metaType) == modelKind
+		},
+		"enum": func(metaType types.MetaType) types.MetaEnum {
+			enum, _ := self.resolver.enum(metaType)
+			return enum
+		},
+		"model": func(metaType types.MetaType) types.MetaModel {
+			model, _ := self.resolver.model(metaType)
+			return model
+		},
+		"isOptional": func(field types.MetaModelField) bool {
+			return field.Cardinality == types.ZeroOrOne
+		},
+		"isOne": func(field types.MetaModelField) bool {
+			return field.Cardinality == types.One
+		},
+		"isCollection": func(field types.MetaModelField) bool {
+			return field.Cardinality == types.Collection
+		},
+		"isComposition": func(field types.MetaModelField) bool {
+			return field.Ownership == types.Composition
+		},
+		"isAggregation": func(field types.MetaModelField) bool {
+			return field.Ownership == types.Aggregation
+		},
+		"trait": func(traits []types.MetaTrait, name string) *types.MetaTrait {
+			if trait, found := types.FindTrait(traits, name); found {
+				return &trait
+			}
+			return nil
+		},
+		"hasTrait": func(traits []types.MetaTrait, name string) bool {
+			_, found := types.FindTrait(traits, name)
+			return found
+		},
+		"traitArg": func(traits []types.MetaTrait, name, argument string) string {
+			trait, _ := types.FindTrait(traits, name)
+			value, _ := trait.Argument(argument)
+			return value.Value
+		},
+	}
+}
@@ -0,0 +1,125 @@
+package stages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const TEMPLATE_GINCO = `package roleplaying {
+	enum Clan {
+		literals {
+			Brujah
+		}
+	}
+
+	@table(name="characters")
+	model Character {
+		fields {
+			=1 id uuid
+			=? nickname string
+			=* tags string
+			=1 clan Clan
+			-* items Item
+		}
+	}
+
+	model Item {
+		fields {
+			=1 name string
+		}
+	}
+}`
+
+func TestTemplateEmitter(t *testing.T) {
+	dir := writeGincoFiles(t, map[string]string{
+		"interface.tmpl": `{{- output (printf "%s/%s.ts" .Package.Name (kebab .Model.Name)) -}}
+export interface {{.Model.Name}} {
+{{- range .Model.Fields}}
+  {{camel .Name}}{{if isOptional .}}?{{end}}: {{template "_type.tmpl" .}}{{if isCollection .}}[]{{end}};
+{{- end}}
+}
+`,
+		"_type.tmpl": `{{if isEnum .Type}}{{join (enum .Type).Literals " | "}}{{else}}{{mapType .Type "typescript"}}{{end}}`,
+		"table.tmpl": `{{- with traitArg .Model.Traits "table" "name"}}{{output "tables.txt"}}{{.}}{{end}}`,
+		"index.package.tmpl": `{{- output (printf "%s/index.ts" .Package.Name) -}}
+{{- range .Package.Models}}
+export * from "./{{kebab .Name}}"; // {{plural (snake .Name)}}
+{{- end}}
+`,
+	})
+
+	emitter, err := NewTemplateEmitter(dir)
+	assert.NoError(t, err)
+
+	results, err := emitter.GenerateFile(parseGinco(t, TEMPLATE_GINCO))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"roleplaying/character.ts", "tables.txt", "roleplaying/item.ts", "roleplaying/index.ts"}, resultPaths(results))
+	assert.Equal(t, `export interface Character {
+  id: string;
+  nickname?: string;
+  tags: string[];
+  clan: Brujah;
+  items: Item[];
+}
+`, results[0].Content)
+	assert.Equal(t, "characters", results[1].Content)
+	assert.Equal(t, `
+export * from "./character"; // characters
+export * from "./item"; // items
+`, results[3].Content)
+}
+
+func TestTemplateEmitterOutputs(t *testing.T) {
+	dir := writeGincoFiles(t, map[string]string{
+		"split.tmpl": `{{output "a.txt"}}a{{output "b.txt"}}b`,
+	})
+
+	emitter, err := NewTemplateEmitter(dir)
+	assert.NoError(t, err)
+
+	results, err := emitter.GenerateFile(parseGinco(t, `package p {
+	model A {
+		fields {
+			=1 id uuid
+		}
+	}
+}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []ModelEmitterResult{{Path: "a.txt", Content: "a"}, {Path: "b.txt", Content: "b"}}, results)
+}
+
+func TestTemplateEmitterErrors(t *testing.T) {
+	_, err := NewTemplateEmitter(t.TempDir())
+	assertErrorContains(t, err, []string{"no .tmpl templates found"})
+
+	dir := writeGincoFiles(t, map[string]string{"broken.tmpl": `{{if}}`})
+	_, err = NewTemplateEmitter(dir)
+	assertErrorContains(t, err, []string{"broken.tmpl"})
+
+	dir = writeGincoFiles(t, map[string]string{"unassigned.tmpl": `{{.Model.Name}}`})
+	emitter, err := NewTemplateEmitter(dir)
+	assert.NoError(t, err)
+	_, err = emitter.GenerateFile(parseGinco(t, TEMPLATE_GINCO))
+	assertErrorContains(t, err, []string{"model roleplaying.Character: template unassigned.tmpl writes content before calling output"})
+}
+
+func TestTemplateEmitterRequiresFile(t *testing.T) {
+	dir := writeGincoFiles(t, map[string]string{
+		"enum.tmpl": `{{output "enum.txt"}}{{isEnum (index .Model.Fields 3).Type}}`,
+	})
+
+	emitter, err := NewTemplateEmitter(dir)
+	assert.NoError(t, err)
+
+	file := parseGinco(t, TEMPLATE_GINCO)
+	model := file.Packages[0].Models[0]
+	_, err = emitter.Generate(model)
+	assertErrorContains(t, err, []string{"types of the model are unknown", "GenerateFile"})
+
+	emitter, err = emitter.forFile(file)
+	assert.NoError(t, err)
+	results, err := emitter.Generate(model)
+	assert.NoError(t, err)
+	assert.Equal(t, []ModelEmitterResult{{Path: "enum.txt", Content: "true"}}, results)
+}
@@ -0,0 +1,98 @@
+package stages
+
+import (
+	"fmt"
+
+	"github.com/trudso/ginco/types"
+)
+
+// typeKind tells what a field type refers to
+type typeKind int
+
+const (
+	primitiveKind typeKind = iota
+	enumKind
+	modelKind
+)
+
+// typeResolver resolves field types to the primitive, enum or model they
+// refer to. The zero value only knows the built-in primitives and treats
+// every other type as a model.
+type typeResolver struct {
+	primitives *types.PrimitiveRegistry
+	enums      map[string]types.MetaEnum
+	models     map[string]types.MetaModel
+}
+
+func newTypeResolver(file types.MetaFile) (typeResolver, error) {
+	resolver := typeResolver{
+		primitives: types.NewPrimitiveRegistry(),
+		enums:      map[string]types.MetaEnum{},
+		models:     map[string]types.MetaModel{},
+	}
+
+	if err := resolver.primitives.RegisterScalars(file); err != nil {
+		return resolver, err
+	}
+
+	for _, pkg := range file.Packages {
+		for _, enum := range pkg.Enums {
+			resolver.enums[qualifiedName(pkg.Name, enum.Name)] = enum
+		}
+
+		for _, model := range pkg.Models {
+			resolver.models[qualifiedName(pkg.Name, model.Name)] = model
+		}
+	}
+
+	return resolver, nil
+}
+
+// resolving fails for the zero value. A model alone does not tell which
+// scalars, enums and models its field types refer to, so an emitter can
+// only be used as a ModelEmitter once it has the resolver of the file
+// declaring its models, and calls resolving before generating.
+func (self typeResolver) resolving(usage string) error {
+	if self.primitives == nil {
+		return fmt.Errorf("the types of the model are unknown, %s", usage)
+	}
+
+	return nil
+}
+
+func (self typeResolver) registry() *types.PrimitiveRegistry {
+	if self.primitives == nil {
+		return types.NewPrimitiveRegistry()
+	}
+
+	return self.primitives
+}
+
+func (self typeResolver) kind(metaType types.MetaType) typeKind {
+	if _, found := self.registry().Lookup(metaType); found {
+		return primitiveKind
+	}
+
+	if _, found := self.enums[formatMetaType(metaType)]; found {
+		return enumKind
+	}
+
+	return modelKind
+}
+
+// mapPrimitive returns the representation of a primitive type in target
+func (self typeResolver) mapPrimitive(metaType types.MetaType, target types.Target) (types.PrimitiveMapping, bool) {
+	return self.registry().Map(metaType, target)
+}
+
+// enum returns the enum metaType refers to
+func (self typeResolver) enum(metaType types.MetaType) (types.MetaEnum, bool) {
+	enum, found := self.enums[formatMetaType(metaType)]
+	return enum, found
+}
+
+// model returns the model metaType refers to
+func (self typeResolver) model(metaType types.MetaType) (types.MetaModel, bool) {
+	model, found := self.models[formatMetaType(metaType)]
+	return model, found
+}
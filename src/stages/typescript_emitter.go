@@ -0,0 +1,165 @@
+package stages
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/trudso/ginco/types"
+)
+
+/*
+	package horror {
+		enum Clan {
+			literals {
+				Brujah
+				Ventrue
+			}
+		}
+
+		model Vampire {
+			fields {
+				=1 clan Clan
+				=? nickname string
+				-1 sire roleplaying.Character
+			}
+		}
+	}
+
+generates horror.ts
+
+	import type * as roleplaying from "./roleplaying";
+
+	export type Clan = "Brujah" | "Ventrue";
+
+	export interface Vampire {
+	  clan: Clan;
+	  nickname?: string;
+	  sire: roleplaying.Character;
+	}
+*/
+
+// TypeScriptEmitter generates a TypeScript module per package holding an
+// interface per model and a type per enum. ZeroOrOne fields are optional
+// and collections are arrays. Other packages are imported from the
+// modules next to it.
+type TypeScriptEmitter struct {
+	// ConstEnums generates a const object per enum along with the union
+	// of its values, instead of only the union of string literals
+	ConstEnums bool
+
+	resolver typeResolver
+}
+
+func (self TypeScriptEmitter) Name() string {
+	return "typescript"
+}
+
+func (self TypeScriptEmitter) GenerateFile(file types.MetaFile) ([]ModelEmitterResult, error) {
+	resolver, err := newTypeResolver(file)
+	if err != nil {
+		return []ModelEmitterResult{}, err
+	}
+	self.resolver = resolver
+
+	results := []ModelEmitterResult{}
+	for _, pkg := range file.Packages {
+		results = append(results, ModelEmitterResult{Path: pkg.Name + ".ts", Content: self.generateModule(pkg)})
+	}
+
+	return results, nil
+}
+
+func (self TypeScriptEmitter) generateModule(pkg types.MetaPackage) string {
+	imports := map[string]bool{}
+	declarations := []string{}
+	for _, enum := range pkg.Enums {
+		declarations = append(declarations, self.enum(enum))
+	}
+
+	for _, model := range pkg.Models {
+		declarations = append(declarations, self.model(pkg.Name, model, imports))
+	}
+
+	module := strings.Builder{}
+	module.WriteString(GENERATED_HEADER + "\n")
+	if len(imports) > 0 {
+		module.WriteString("\n")
+	}
+
+	packages := []string{}
+	for imported := range imports {
+		packages = append(packages, imported)
+	}
+	slices.Sort(packages)
+	for _, imported := range packages {
+		fmt.Fprintf(&module, "import type * as %s from \"./%s\";\n", imported, imported)
+	}
+
+	for _, declaration := range declarations {
+		module.WriteString("\n" + declaration)
+	}
+
+	return module.String()
+}
+
+func (self TypeScriptEmitter) enum(enum types.MetaEnum) string {
+	values := []string{}
+	for _, literal := range enum.Literals {
+		values = append(values, fmt.Sprintf("%q", literal))
+	}
+
+	if len(values) == 0 {
+		values = []string{"never"}
+	}
+
+	if !self.ConstEnums {
+		return fmt.Sprintf("export type %s = %s;\n", enum.Name, strings.Join(values, " | "))
+	}
+
+	declaration := strings.Builder{}
+	fmt.Fprintf(&declaration, "export const %s = {\n", enum.Name)
+	for _, literal := range enum.Literals {
+		fmt.Fprintf(&declaration, "  %s: %q,\n", literal, literal)
+	}
+	declaration.WriteString("} as const;\n\n")
+	fmt.Fprintf(&declaration, "export type %s = (typeof %s)[keyof typeof %s];\n", enum.Name, enum.Name, enum.Name)
+
+	return declaration.String()
+}
+
+func (self TypeScriptEmitter) model(pkg string, model types.MetaModel, imports map[string]bool) string {
+	declaration := strings.Builder{}
+	fmt.Fprintf(&declaration, "export interface %s {\n", model.Name)
+	for _, field := range model.Fields {
+		optional := ""
+		if field.Cardinality == types.ZeroOrOne {
+			optional = "?"
+		}
+
+		fieldType := self.typeName(pkg, field.Type, imports)
+		if field.Cardinality == types.Collection {
+			fieldType += "[]"
+		}
+
+		fmt.Fprintf(&declaration, "  %s%s: %s;\n", field.Name, optional, fieldType)
+	}
+	declaration.WriteString("}\n")
+
+	return declaration.String()
+}
+
+// typeName returns the TypeScript type of metaType, adding the package it
+// is declared in to imports
+func (self TypeScriptEmitter) typeName(pkg string, metaType types.MetaType, imports map[string]bool) string {
+	if mapping, found := self.resolver.mapPrimitive(metaType, types.TargetTypeScript); found {
+		return mapping.Type
+	}
+
+	if metaType.Package == "" || metaType.Package == pkg {
+		return metaType.Name
+	}
+
+	imports[metaType.Package] = true
+	return metaType.Package + "." + metaType.Name
+}
@@ -0,0 +1,84 @@
+package stages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const TYPESCRIPT_GINCO = `package roleplaying {
+	enum Clan {
+		literals {
+			Brujah
+			Ventrue
+		}
+	}
+
+	model Character {
+		fields {
+			=1 id uuid
+			=? nickname string
+			=1 clan Clan
+			=* tags string
+			=1 level int32
+			=1 alive bool
+		}
+	}
+}
+
+package horror {
+	model Vampire {
+		fields {
+			-1 sire roleplaying.Character
+			=* clans roleplaying.Clan
+			-? childe Vampire
+		}
+	}
+}`
+
+func TestTypeScriptEmitter(t *testing.T) {
+	file := parseGinco(t, TYPESCRIPT_GINCO)
+
+	results, err := TypeScriptEmitter{}.GenerateFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"roleplaying.ts", "horror.ts"}, resultPaths(results))
+	assert.Equal(t, `// Code generated by ginco. DO NOT EDIT.
+
+export type Clan = "Brujah" | "Ventrue";
+
+export interface Character {
+  id: string;
+  nickname?: string;
+  clan: Clan;
+  tags: string[];
+  level: number;
+  alive: boolean;
+}
+`, results[0].Content)
+	assert.Equal(t, `// Code generated by ginco. DO NOT EDIT.
+
+import type * as roleplaying from "./roleplaying";
+
+export interface Vampire {
+  sire: roleplaying.Character;
+  clans: roleplaying.Clan[];
+  childe?: Vampire;
+}
+`, results[1].Content)
+}
+
+func TestTypeScriptEmitterConstEnums(t *testing.T) {
+	file := parseGinco(t, CLAN_GINCO)
+
+	results, err := TypeScriptEmitter{ConstEnums: true}.GenerateFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, `// Code generated by ginco. DO NOT EDIT.
+
+export const Clan = {
+  Brujah: "Brujah",
+  Ventrue: "Ventrue",
+} as const;
+
+export type Clan = (typeof Clan)[keyof typeof Clan];
+`, results[0].Content)
+}
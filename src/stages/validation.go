@@ -0,0 +1,110 @@
+package stages
+
+import (
+	"fmt"
+
+	"github.com/trudso/ginco/types"
+)
+
+// symbolTable holds the position of every model, enum and scalar by package and name
+type symbolTable map[string]map[string]types.Position
+
+func (self symbolTable) declare(pkg, name string, position types.Position) (types.Position, bool) {
+	if self[pkg] == nil {
+		self[pkg] = map[string]types.Position{}
+	}
+
+	if existing, found := self[pkg][name]; found {
+		return existing, false
+	}
+
+	self[pkg][name] = position
+	return position, true
+}
+
+func (self symbolTable) resolves(metaType types.MetaType) bool {
+	if _, found := self[metaType.Package][metaType.Name]; found {
+		return true
+	}
+
+	return metaType.Package == "" && types.IsBuiltinPrimitive(metaType.Name)
+}
+
+// ValidateMetaFile type checks a parsed MetaFile and reports every
+// unresolved type and duplicate declaration found
+func ValidateMetaFile(file types.MetaFile) error {
+	diagnostics := types.Diagnostics{}
+	report := func(code string, position types.Position, format string, args ...any) *types.Diagnostic {
+		diagnostics = append(diagnostics, types.Diagnostic{
+			Severity: types.SeverityError,
+			Code:     code,
+			Message:  fmt.Sprintf(format, args...),
+			Position: position,
+		})
+		return &diagnostics[len(diagnostics)-1]
+	}
+
+	packages := map[string]types.Position{}
+	symbols := symbolTable{}
+	for _, pkg := range file.Packages {
+		if first, found := packages[pkg.Name]; found {
+			report(types.CodeDuplicatePackage, pkg.Position, "Duplicate package %q, first declared at %s", pkg.Name, first)
+		} else {
+			packages[pkg.Name] = pkg.Position
+		}
+
+		for _, model := range pkg.Models {
+			if first, ok := symbols.declare(pkg.Name, model.Name, model.Position); !ok {
+				report(types.CodeDuplicateModel, model.Position, "Duplicate model %q in package %s, first declared at %s", model.Name, pkg.Name, first)
+			}
+
+			fields := map[string]types.Position{}
+			for _, field := range model.Fields {
+				if first, found := fields[field.Name]; found {
+					report(types.CodeDuplicateField, field.Position, "Duplicate field %q in model %s.%s, first declared at %s", field.Name, pkg.Name, model.Name, first)
+					continue
+				}
+
+				fields[field.Name] = field.Position
+			}
+		}
+
+		for _, enum := range pkg.Enums {
+			if first, ok := symbols.declare(pkg.Name, enum.Name, enum.Position); !ok {
+				report(types.CodeDuplicateEnum, enum.Position, "Duplicate enum %q in package %s, first declared at %s", enum.Name, pkg.Name, first)
+			}
+		}
+
+		for _, scalar := range pkg.Scalars {
+			if first, ok := symbols.declare(pkg.Name, scalar.Name, scalar.Position); !ok {
+				report(types.CodeDuplicateScalar, scalar.Position, "Duplicate scalar %q in package %s, first declared at %s", scalar.Name, pkg.Name, first)
+			}
+
+			if !types.IsBuiltinPrimitive(scalar.Base) {
+				report(types.CodeUnknownBasePrimitive, scalar.Position, "Unknown base primitive %q for scalar %s.%s", scalar.Base, pkg.Name, scalar.Name)
+			}
+		}
+	}
+
+	for _, pkg := range file.Packages {
+		for _, model := range pkg.Models {
+			for _, field := range model.Fields {
+				if !symbols.resolves(field.Type) {
+					diagnostic := report(types.CodeUnresolvedType, field.Type.Position, "Unresolved type %s for field %s in model %s.%s", formatMetaType(field.Type), field.Name, pkg.Name, model.Name)
+					diagnostic.Length = field.Type.Length
+					diagnostic.Hint = fmt.Sprintf("Declare %s in package %s, or import the file declaring it", field.Type.Name, field.Type.Package)
+				}
+			}
+		}
+	}
+
+	return diagnostics.Err()
+}
+
+func formatMetaType(metaType types.MetaType) string {
+	if metaType.Package == "" {
+		return metaType.Name
+	}
+
+	return metaType.Package + PACKAGE_SEPARATOR + metaType.Name
+}
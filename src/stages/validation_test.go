@@ -0,0 +1,143 @@
+package stages
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trudso/ginco/types"
+)
+
+func TestValidateMetaFile(t *testing.T) {
+	testCases := []struct {
+		content             string
+		expectedErrorValues []string
+	}{
+		{`package roleplaying {
+	model Character {
+		fields {
+			=1 id uuid
+			=? name string
+			-1 type CharacterType
+			=* skills Skill
+		}
+	}
+
+	model Skill {
+		fields {
+			=1 name string
+		}
+	}
+
+	enum CharacterType {
+		literals {
+			player
+		}
+	}
+}`, nil},
+		{`package roleplaying {
+	model Character {
+		fields {
+			=1 id uuid
+			-1 type CharacterType
+		}
+	}
+}`, []string{"5:12: Unresolved type roleplaying.CharacterType for field type in model roleplaying.Character"}},
+		{`package roleplaying {
+	model Character {
+		fields {
+			=1 id uuid
+			=? id string
+		}
+	}
+}`, []string{`5:4: Duplicate field "id" in model roleplaying.Character, first declared at 4:4`}},
+		{`package roleplaying {
+	model Character {}
+	model Character {}
+	enum Character {}
+}`, []string{
+			`3:2: Duplicate model "Character" in package roleplaying, first declared at 2:2`,
+			`4:2: Duplicate enum "Character" in package roleplaying, first declared at 2:2`,
+		}},
+		{"package a {}\npackage a {}", []string{`2:1: Duplicate package "a", first declared at 1:1`}},
+		{`package horror {
+	model Vampire {
+		fields {
+			=1 base roleplaying.Character
+			=1 name roleplaying.string
+		}
+	}
+}`, []string{
+			"4:12: Unresolved type roleplaying.Character",
+			"5:12: Unresolved type roleplaying.string",
+		}},
+		{`package people {
+	scalar Email string
+	scalar Email string
+	scalar Phone phoneNumber
+
+	model Person {
+		fields {
+			=1 email Email
+			=? phone Phone
+		}
+	}
+}`, []string{
+			`3:2: Duplicate scalar "Email" in package people, first declared at 2:2`,
+			`4:2: Unknown base primitive "phoneNumber" for scalar people.Phone`,
+		}},
+	}
+
+	for _, tc := range testCases {
+		file, _, err := parseMetaFile(tc.content, 0)
+		assert.NoError(t, err)
+
+		err = ValidateMetaFile(file)
+		assertErrorContains(t, err, tc.expectedErrorValues)
+	}
+}
+
+func TestValidateMetaFileAcrossImports(t *testing.T) {
+	dir := writeGincoFiles(t, map[string]string{
+		"roleplaying.ginco": `package roleplaying {
+	model Character {}
+}`,
+		"horror.ginco": `import ./roleplaying.ginco
+
+package horror {
+	model Vampire {
+		fields {
+			=1 base roleplaying.Character
+			-1 clan Clan
+		}
+	}
+}`,
+	})
+
+	path := filepath.Join(dir, "horror.ginco")
+	file, err := GincoMetaFileParser{}.ParseFile(path)
+	assert.NoError(t, err)
+
+	err = ValidateMetaFile(file)
+	assertErrorContains(t, err, []string{path + ":7:12: Unresolved type horror.Clan"})
+	assert.NotContains(t, err.Error(), "roleplaying.Character")
+}
+
+func TestValidateMetaFileReportsTypeSpans(t *testing.T) {
+	file, _, err := parseMetaFile(`package horror {
+	model Vampire {
+		fields {
+			=1 base roleplaying.Character
+			-1 clan Clan
+		}
+	}
+}`, 0)
+	assert.NoError(t, err)
+
+	var diagnostics types.Diagnostics
+	assert.True(t, errors.As(ValidateMetaFile(file), &diagnostics))
+	assert.Equal(t, 2, len(diagnostics))
+	assert.Equal(t, len("roleplaying.Character"), diagnostics[0].Length)
+	assert.Equal(t, len("Clan"), diagnostics[1].Length)
+}
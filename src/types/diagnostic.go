@@ -0,0 +1,101 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+	SeverityInfo
+)
+
+func (self Severity) String() string {
+	switch self {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	}
+
+	return fmt.Sprintf("Severity(%d)", int(self))
+}
+
+// Diagnostic codes
+const (
+	CodeSyntax               = "syntax"
+	CodeImport               = "import"
+	CodeImportCycle          = "import-cycle"
+	CodeUnresolvedType       = "unresolved-type"
+	CodeUnknownBasePrimitive = "unknown-base-primitive"
+	CodeDuplicatePackage     = "duplicate-package"
+	CodeDuplicateModel       = "duplicate-model"
+	CodeDuplicateEnum        = "duplicate-enum"
+	CodeDuplicateScalar      = "duplicate-scalar"
+	CodeDuplicateField       = "duplicate-field"
+	CodeInvalidTrait         = "invalid-trait"
+	CodeInheritanceCycle     = "inheritance-cycle"
+	CodeInheritanceConflict  = "inheritance-conflict"
+)
+
+// Diagnostic is a problem found in a .ginco source,
+// reported at the position it was found
+type Diagnostic struct {
+	Severity Severity
+	Code     string
+	Message  string
+	Position Position
+	// Length is the number of bytes spanned from Position, 0 if unknown
+	Length int
+	Hint   string
+}
+
+func (self Diagnostic) Error() string {
+	return fmt.Sprintf("%s: %s", self.Position, self.Message)
+}
+
+// Diagnostics collects every problem found by a stage
+type Diagnostics []Diagnostic
+
+func (self Diagnostics) Error() string {
+	messages := make([]string, len(self))
+	for i, diagnostic := range self {
+		messages[i] = diagnostic.Error()
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+// Unwrap allows errors.As to find the individual diagnostics
+func (self Diagnostics) Unwrap() []error {
+	errs := make([]error, len(self))
+	for i, diagnostic := range self {
+		errs[i] = diagnostic
+	}
+
+	return errs
+}
+
+func (self Diagnostics) HasErrors() bool {
+	for _, diagnostic := range self {
+		if diagnostic.Severity == SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Err returns the diagnostics as an error, or nil if there are none
+func (self Diagnostics) Err() error {
+	if len(self) == 0 {
+		return nil
+	}
+
+	return self
+}
@@ -0,0 +1,48 @@
+package types
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiagnosticError(t *testing.T) {
+	testCases := []struct {
+		diagnostic    Diagnostic
+		expectedError string
+	}{
+		{Diagnostic{Message: "No scope found", Position: Position{Line: 3, Column: 5}}, "3:5: No scope found"},
+		{Diagnostic{Message: "No scope found", Position: Position{File: "a.ginco", Line: 3, Column: 5}}, "a.ginco:3:5: No scope found"},
+		{Diagnostic{Message: "Import cycle detected", Position: Position{File: "a.ginco"}}, "a.ginco: Import cycle detected"},
+	}
+
+	for _, tc := range testCases {
+		assert.EqualError(t, tc.diagnostic, tc.expectedError)
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	assert.Nil(t, Diagnostics{}.Err())
+	assert.False(t, Diagnostics{}.HasErrors())
+
+	diagnostics := Diagnostics{
+		{Severity: SeverityWarning, Code: "a", Message: "first", Position: Position{Line: 1, Column: 1}},
+		{Severity: SeverityError, Code: "b", Message: "second", Position: Position{Line: 2, Column: 1}},
+	}
+	assert.True(t, diagnostics.HasErrors())
+	assert.False(t, diagnostics[:1].HasErrors())
+
+	err := diagnostics.Err()
+	assert.EqualError(t, err, "1:1: first\n2:1: second")
+
+	var asDiagnostics Diagnostics
+	assert.True(t, errors.As(err, &asDiagnostics))
+	assert.Equal(t, "b", asDiagnostics[1].Code)
+}
+
+func TestSeverityString(t *testing.T) {
+	assert.Equal(t, "error", SeverityError.String())
+	assert.Equal(t, "warning", SeverityWarning.String())
+	assert.Equal(t, "info", SeverityInfo.String())
+}
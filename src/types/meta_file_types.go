@@ -1,5 +1,7 @@
 package types
 
+import "fmt"
+
 type Cardinality int
 
 const (
@@ -15,43 +17,157 @@ const (
 	Aggregation
 )
 
+// Position points at a location in a .ginco source
+type Position struct {
+	File   string
+	Line   int
+	Column int
+	Offset int
+}
+
+func (self Position) String() string {
+	if self.Line == 0 {
+		return self.File
+	}
+
+	if self.File == "" {
+		return fmt.Sprintf("%d:%d", self.Line, self.Column)
+	}
+
+	return fmt.Sprintf("%s:%d:%d", self.File, self.Line, self.Column)
+}
+
 // Data structure
 type MetaFile struct {
+	Imports  []MetaImport
 	Packages []MetaPackage
 }
 
+// MetaImport is an import directive, Path is relative to the importing file
+type MetaImport struct {
+	Position Position
+	Path     string
+}
+
 type MetaPackage struct {
-	Name   string
-	Models []MetaModel
+	Position Position
+	Name     string
+	Models   []MetaModel
+	Enums    []MetaEnum
+	Scalars  []MetaScalar
 }
 
 type MetaTrait struct {
-	Name string
+	Position  Position
+	Name      string
+	Arguments []MetaTraitArgument
+}
+
+// Argument returns the named argument called name
+func (self MetaTrait) Argument(name string) (MetaTraitArgument, bool) {
+	for _, argument := range self.Arguments {
+		if argument.Name == name {
+			return argument, true
+		}
+	}
+
+	return MetaTraitArgument{}, false
+}
+
+// Positional returns the positional argument at index
+func (self MetaTrait) Positional(index int) (MetaTraitArgument, bool) {
+	for _, argument := range self.Arguments {
+		if argument.Name != "" {
+			continue
+		}
+
+		if index == 0 {
+			return argument, true
+		}
+		index--
+	}
+
+	return MetaTraitArgument{}, false
+}
+
+// FindTrait returns the first trait called name
+func FindTrait(traits []MetaTrait, name string) (MetaTrait, bool) {
+	for _, trait := range traits {
+		if trait.Name == name {
+			return trait, true
+		}
+	}
+
+	return MetaTrait{}, false
+}
+
+type TraitValueKind int
+
+const (
+	StringValue TraitValueKind = iota
+	NumberValue
+	BoolValue
+	IdentifierValue
+	TypeValue
+)
+
+// MetaTraitArgument is an argument of a trait, e.g. name="characters" in
+// @table(name="characters"). Positional arguments have no Name.
+type MetaTraitArgument struct {
+	Position Position
+	Name     string
+	Kind     TraitValueKind
+	// Value is the unquoted string, the number, true/false or the identifier
+	Value string
+	// Type is the referenced type of identifier and type values
+	Type MetaType
 }
 
 type MetaModel struct {
-	Name   string
+	Position Position
+	Package  string
+	Name     string
+	// Base is the model this model inherits from, if any
+	Base   *MetaType
 	Traits []MetaTrait
 	Fields []MetaModelField
 }
 
 type MetaModelField struct {
-	Name string
-	Type MetaType
+	Position Position
+	Name     string
+	Type     MetaType
 	// Kind        string ?
 	Cardinality Cardinality
 	Ownership   Ownership
 	Nullable    bool
 	Traits      []MetaTrait
+	// Inherited is set for fields merged in from the base model
+	Inherited bool
 }
 
 type MetaType struct {
+	Position Position
+	// Length is the number of bytes the reference spans in the source
+	Length  int
 	Package string
 	Name    string
 }
 
 type MetaEnum struct {
+	Position Position
+	Package  string
 	Name     string
 	Traits   []MetaTrait
 	Literals []string
 }
+
+// MetaScalar is a custom primitive based on a built-in primitive,
+// optionally overriding how it is mapped to some targets
+type MetaScalar struct {
+	Position Position
+	Name     string
+	Base     string
+	Traits   []MetaTrait
+	Mappings map[Target]string
+}
@@ -0,0 +1,165 @@
+package types
+
+import "fmt"
+
+// Target identifies an output format primitives can be mapped to
+type Target string
+
+const (
+	TargetGo         Target = "go"
+	TargetPostgres   Target = "postgres"
+	TargetSQLite     Target = "sqlite"
+	TargetJSONSchema Target = "jsonschema"
+	TargetTypeScript Target = "typescript"
+	TargetProtobuf   Target = "protobuf"
+	TargetGraphQL    Target = "graphql"
+)
+
+// PrimitiveMapping is the representation of a primitive in a target.
+// Format is optional and refines Type, e.g. the JSON Schema format "uuid"
+// for the type "string".
+type PrimitiveMapping struct {
+	Type   string
+	Format string
+}
+
+type MetaPrimitive struct {
+	// Package is empty for built-in primitives
+	Package  string
+	Name     string
+	Mappings map[Target]PrimitiveMapping
+}
+
+// primitive creates a built-in primitive from its mapping in each target
+func primitive(name, goType, postgres, sqlite, jsonSchema, jsonSchemaFormat, typescript, protobuf, graphql string) MetaPrimitive {
+	return MetaPrimitive{
+		Name: name,
+		Mappings: map[Target]PrimitiveMapping{
+			TargetGo:         {Type: goType},
+			TargetPostgres:   {Type: postgres},
+			TargetSQLite:     {Type: sqlite},
+			TargetJSONSchema: {Type: jsonSchema, Format: jsonSchemaFormat},
+			TargetTypeScript: {Type: typescript},
+			TargetProtobuf:   {Type: protobuf},
+			TargetGraphQL:    {Type: graphql},
+		},
+	}
+}
+
+var builtinPrimitives = []MetaPrimitive{
+	primitive("uuid", "string", "UUID", "TEXT", "string", "uuid", "string", "string", "ID"),
+	primitive("string", "string", "TEXT", "TEXT", "string", "", "string", "string", "String"),
+	primitive("number", "float64", "DOUBLE PRECISION", "REAL", "number", "", "number", "double", "Float"),
+	primitive("int32", "int32", "INTEGER", "INTEGER", "integer", "int32", "number", "int32", "Int"),
+	// GraphQL Int is 32 bit and Float loses precision, so int64 is a String
+	primitive("int64", "int64", "BIGINT", "INTEGER", "integer", "int64", "number", "int64", "String"),
+	primitive("float32", "float32", "REAL", "REAL", "number", "float", "number", "float", "Float"),
+	primitive("float64", "float64", "DOUBLE PRECISION", "REAL", "number", "double", "number", "double", "Float"),
+	primitive("decimal", "string", "NUMERIC", "NUMERIC", "string", "decimal", "string", "string", "String"),
+	primitive("bool", "bool", "BOOLEAN", "INTEGER", "boolean", "", "boolean", "bool", "Boolean"),
+	primitive("bytes", "[]byte", "BYTEA", "BLOB", "string", "byte", "string", "bytes", "String"),
+	primitive("date", "time.Time", "DATE", "TEXT", "string", "date", "string", "string", "String"),
+	primitive("datetime", "time.Time", "TIMESTAMPTZ", "TEXT", "string", "date-time", "string", "google.protobuf.Timestamp", "String"),
+	primitive("duration", "time.Duration", "INTERVAL", "INTEGER", "string", "duration", "string", "google.protobuf.Duration", "String"),
+}
+
+// BuiltinPrimitives returns the primitives known to every .ginco file
+func BuiltinPrimitives() []MetaPrimitive {
+	primitives := make([]MetaPrimitive, len(builtinPrimitives))
+	copy(primitives, builtinPrimitives)
+	return primitives
+}
+
+func IsBuiltinPrimitive(name string) bool {
+	for _, primitive := range builtinPrimitives {
+		if primitive.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PrimitiveRegistry maps the built-in primitives and the custom scalars
+// declared in .ginco files to their representation in each target
+type PrimitiveRegistry struct {
+	primitives map[primitiveKey]MetaPrimitive
+}
+
+type primitiveKey struct {
+	Package string
+	Name    string
+}
+
+func NewPrimitiveRegistry() *PrimitiveRegistry {
+	registry := &PrimitiveRegistry{
+		primitives: map[primitiveKey]MetaPrimitive{},
+	}
+
+	for _, primitive := range builtinPrimitives {
+		registry.primitives[primitiveKey{Name: primitive.Name}] = primitive
+	}
+
+	return registry
+}
+
+func (self *PrimitiveRegistry) Register(primitive MetaPrimitive) error {
+	key := primitiveKey{Package: primitive.Package, Name: primitive.Name}
+	if _, found := self.primitives[key]; found {
+		return fmt.Errorf("primitive %s is already registered", primitive.Name)
+	}
+
+	self.primitives[key] = primitive
+	return nil
+}
+
+// RegisterScalars registers every scalar declared in file. A scalar
+// inherits the mappings of its base primitive unless it overrides them.
+func (self *PrimitiveRegistry) RegisterScalars(file MetaFile) error {
+	for _, pkg := range file.Packages {
+		for _, scalar := range pkg.Scalars {
+			base, found := self.primitives[primitiveKey{Name: scalar.Base}]
+			if !found {
+				return fmt.Errorf("%s: unknown base primitive %q for scalar %s", scalar.Position, scalar.Base, scalar.Name)
+			}
+
+			primitive := MetaPrimitive{
+				Package:  pkg.Name,
+				Name:     scalar.Name,
+				Mappings: map[Target]PrimitiveMapping{},
+			}
+			for target, mapping := range base.Mappings {
+				primitive.Mappings[target] = mapping
+			}
+			for target, mappedType := range scalar.Mappings {
+				mapping := primitive.Mappings[target]
+				mapping.Type = mappedType
+				primitive.Mappings[target] = mapping
+			}
+
+			if err := self.Register(primitive); err != nil {
+				return fmt.Errorf("%s: %w", scalar.Position, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Lookup returns the primitive a type refers to. Built-in primitives
+// are unqualified, scalars are qualified by the package declaring them.
+func (self *PrimitiveRegistry) Lookup(metaType MetaType) (MetaPrimitive, bool) {
+	primitive, found := self.primitives[primitiveKey{Package: metaType.Package, Name: metaType.Name}]
+	return primitive, found
+}
+
+// Map returns the representation of a primitive type in target
+func (self *PrimitiveRegistry) Map(metaType MetaType, target Target) (PrimitiveMapping, bool) {
+	primitive, found := self.Lookup(metaType)
+	if !found {
+		return PrimitiveMapping{}, false
+	}
+
+	mapping, found := primitive.Mappings[target]
+	return mapping, found
+}
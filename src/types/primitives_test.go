@@ -0,0 +1,79 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrimitiveRegistryBuiltins(t *testing.T) {
+	registry := NewPrimitiveRegistry()
+
+	for _, primitive := range BuiltinPrimitives() {
+		for _, target := range []Target{TargetGo, TargetPostgres, TargetSQLite, TargetJSONSchema, TargetTypeScript, TargetProtobuf, TargetGraphQL} {
+			mapping, found := registry.Map(MetaType{Name: primitive.Name}, target)
+			assert.True(t, found, "%s has no %s mapping", primitive.Name, target)
+			assert.NotEmpty(t, mapping.Type, "%s has no %s mapping", primitive.Name, target)
+		}
+	}
+
+	mapping, found := registry.Map(MetaType{Name: "uuid"}, TargetJSONSchema)
+	assert.True(t, found)
+	assert.Equal(t, PrimitiveMapping{Type: "string", Format: "uuid"}, mapping)
+
+	_, found = registry.Lookup(MetaType{Package: "roleplaying", Name: "uuid"})
+	assert.False(t, found)
+
+	mapping, _ = registry.Map(MetaType{Name: "int64"}, TargetGraphQL)
+	assert.Equal(t, "String", mapping.Type)
+
+	_, found = registry.Lookup(MetaType{Package: "roleplaying", Name: "Character"})
+	assert.False(t, found)
+
+	assert.True(t, IsBuiltinPrimitive("datetime"))
+	assert.False(t, IsBuiltinPrimitive("Character"))
+}
+
+func TestPrimitiveRegistryScalars(t *testing.T) {
+	registry := NewPrimitiveRegistry()
+	err := registry.RegisterScalars(MetaFile{
+		Packages: []MetaPackage{
+			{
+				Name: "people",
+				Scalars: []MetaScalar{
+					{Name: "Email", Base: "string", Mappings: map[Target]string{TargetPostgres: "VARCHAR(320)"}},
+					{Name: "Timestamp", Base: "datetime"},
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	mapping, found := registry.Map(MetaType{Package: "people", Name: "Email"}, TargetPostgres)
+	assert.True(t, found)
+	assert.Equal(t, "VARCHAR(320)", mapping.Type)
+
+	mapping, found = registry.Map(MetaType{Package: "people", Name: "Email"}, TargetGo)
+	assert.True(t, found)
+	assert.Equal(t, "string", mapping.Type)
+
+	mapping, found = registry.Map(MetaType{Package: "people", Name: "Timestamp"}, TargetJSONSchema)
+	assert.True(t, found)
+	assert.Equal(t, PrimitiveMapping{Type: "string", Format: "date-time"}, mapping)
+
+	_, found = registry.Lookup(MetaType{Position: Position{Line: 3, Column: 12}, Package: "people", Name: "Email"})
+	assert.True(t, found)
+
+	_, found = registry.Lookup(MetaType{Package: "other", Name: "Email"})
+	assert.False(t, found)
+
+	err = registry.RegisterScalars(MetaFile{
+		Packages: []MetaPackage{{Name: "people", Scalars: []MetaScalar{{Name: "Email", Base: "string"}}}},
+	})
+	assert.ErrorContains(t, err, "primitive Email is already registered")
+
+	err = registry.RegisterScalars(MetaFile{
+		Packages: []MetaPackage{{Name: "people", Scalars: []MetaScalar{{Name: "Phone", Base: "phoneNumber"}}}},
+	})
+	assert.ErrorContains(t, err, `unknown base primitive "phoneNumber" for scalar Phone`)
+}